@@ -2,15 +2,20 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -18,8 +23,10 @@ import (
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/calculator"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/formatter"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/metrics"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/security"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/workerpool"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/pkg/rpc"
 )
 
 var (
@@ -45,16 +52,44 @@ func init() {
 func main() {
 	// Parse command-line flags
 	var (
-		outputPath  string
-		printStdout bool
-		cpuProfile  string
-		memProfile  string
+		outputPath        string
+		printStdout       bool
+		cpuProfile        string
+		memProfile        string
+		verifyCount       int
+		outputFormat      string
+		checkpointDir     string
+		algorithmName     string
+		benchmarkAll      bool
+		metricsAddr       string
+		deadline          time.Duration
+		workerAddrs       string
+		tlsCert           string
+		tlsKey            string
+		tlsWorkerCA       string
+		verifySample      bool
+		verifySampleCount int
+		workStealing      bool
 	)
 
 	flag.StringVar(&outputPath, "o", "results/pi.txt", "Output file path for pi digits")
 	flag.BoolVar(&printStdout, "print", false, "Print pi to stdout")
 	flag.StringVar(&cpuProfile, "cpuprofile", "", "write cpu profile to file")
 	flag.StringVar(&memProfile, "memprofile", "", "write memory profile to file")
+	flag.IntVar(&verifyCount, "verify", 0, "when N > 0, cross-check a fixed window of up to 16 trailing hex digits (ending 8 short of the last) against an independent BBP computation; N does not set the window size (0 disables)")
+	flag.StringVar(&outputFormat, "format", "text", "output encoding: text, paged, json, binary, or ycruncher")
+	flag.StringVar(&checkpointDir, "checkpoint-dir", "", "directory for on-disk PQT checkpointing, so an interrupted run can resume (disabled if empty)")
+	flag.StringVar(&algorithmName, "algorithm", "chudnovsky", "π algorithm to use: chudnovsky, agm, machin, or ramanujan")
+	flag.BoolVar(&benchmarkAll, "benchmark-all", false, "compute pi with every algorithm and report wall time, peak RSS, and digits/sec instead of the normal single-algorithm run")
+	flag.StringVar(&metricsAddr, "metrics-addr", "", "address to serve Prometheus /metrics and /healthz on, e.g. :9090 (disabled if empty)")
+	flag.DurationVar(&deadline, "deadline", 0, "optional wall-clock deadline for the computation; /healthz reports 503 once it's exceeded (0 disables)")
+	flag.StringVar(&workerAddrs, "workers", "", "comma-separated host:port list of chudnovskyworker processes; when set, PQT ranges are dispatched to them over RPC instead of the local worker pool")
+	flag.StringVar(&tlsCert, "tls-cert", "", "coordinator's TLS client certificate (required with -workers)")
+	flag.StringVar(&tlsKey, "tls-key", "", "coordinator's TLS client private key (required with -workers)")
+	flag.StringVar(&tlsWorkerCA, "tls-worker-ca", "", "CA bundle used to verify worker server certificates (required with -workers)")
+	flag.BoolVar(&verifySample, "verify-sample", false, "spot-check verify-sample-count uniformly random hex digit positions across the full result, rather than just the trailing window -verify checks")
+	flag.IntVar(&verifySampleCount, "verify-sample-count", 16, "number of random positions -verify-sample checks")
+	flag.BoolVar(&workStealing, "work-stealing", false, "use a per-worker work-stealing deque (workerpool.StealPool) instead of the shared priority queue for the local worker pool")
 	flag.Parse()
 
 	// Setup profiling
@@ -129,8 +164,18 @@ func main() {
 	// Create context with cancellation support
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	if deadline > 0 {
+		var deadlineCancel context.CancelFunc
+		ctx, deadlineCancel = context.WithTimeout(ctx, deadline)
+		defer deadlineCancel()
+	}
 
-	// Handle interrupt signals
+	// Handle interrupt signals. No separate checkpoint flush is needed here:
+	// when -checkpoint-dir is set, FileCheckpointStore.Save already fsyncs
+	// after every completed subrange, so there is nothing buffered left to
+	// lose between the last Save and this cancel(); the same -checkpoint-dir
+	// also resumes from those entries on the next run, so there's no
+	// separate -resume flag either.
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
@@ -150,42 +195,142 @@ func main() {
 			progressbar.OptionSetDescription("Computing terms"),
 			progressbar.OptionSetWidth(50),
 		)
+		defer func() {
+			if finishErr := progressBar.Finish(); finishErr != nil {
+				logger.Debug("Failed to finish progress bar", "error", finishErr)
+			}
+		}()
+	}
 
-		// Set progress callback
-		calculator.SetProgressCallback(func(current int64) {
+	// Report progress via a per-computation reporter instead of the old
+	// package-level SetProgressCallback, so it can't race with another
+	// concurrent ComputePQT call in the same process.
+	var termsDone int64
+	progressReporter := calculator.NewFuncReporter(func(e calculator.Event) {
+		var current int64
+		switch ev := e.(type) {
+		case calculator.TermCompleted:
+			current = atomic.AddInt64(&termsDone, 1)
+		case calculator.ChunkCompleted:
+			current = atomic.AddInt64(&termsDone, ev.End-ev.Start)
+		default:
+			return
+		}
+		if progressBar != nil {
+			if setErr := progressBar.Set64(current); setErr != nil {
+				logger.Debug("Failed to update progress bar", "error", setErr)
+			}
+		}
+		metrics.RecordProgress(current)
+	})
+
+	// Create a worker pool if needed: a distributed rpc.Coordinator when
+	// -workers is set, otherwise the local worker pool.
+	var pool calculator.PoolInterface
+	var poolHealth metrics.PoolHealth
+	switch {
+	case workerAddrs != "":
+		tlsConfig, err := coordinatorTLSConfig(tlsCert, tlsKey, tlsWorkerCA)
+		if err != nil {
+			logger.Error("Invalid -workers TLS configuration", "error", err)
+			os.Exit(1)
+		}
+		coordinator := rpc.NewCoordinator(strings.Split(workerAddrs, ","), tlsConfig, func(current int64) {
 			if progressBar != nil {
 				if setErr := progressBar.Set64(current); setErr != nil {
 					logger.Debug("Failed to update progress bar", "error", setErr)
 				}
 			}
+			metrics.RecordProgress(current)
 		})
 		defer func() {
-			if finishErr := progressBar.Finish(); finishErr != nil {
-				logger.Debug("Failed to finish progress bar", "error", finishErr)
+			if closeErr := coordinator.Close(); closeErr != nil {
+				logger.Debug("Failed to close worker connections", "error", closeErr)
 			}
 		}()
-	}
-
-	// Create worker pool if needed
-	var pool *workerpool.Pool
-	if terms > cfg.MinRangeForWorkerPool {
+		pool = coordinator
+		poolHealth = coordinator
+		logger.Info("Dispatching PQT ranges to remote workers", "workers", workerAddrs)
+	case terms > cfg.MinRangeForWorkerPool:
 		poolSize := cfg.WorkerPoolSize
 		if poolSize == 0 {
 			poolSize = calculator.GetNumCPU()
 		}
-		pool = workerpool.New(poolSize)
-		defer pool.Close()
-		logger.Info("Using worker pool", "workers", poolSize)
+		if workStealing {
+			stealPool := workerpool.NewStealPool(poolSize)
+			defer stealPool.Close()
+			pool = stealPool
+			poolHealth = stealPool
+			logger.Info("Using work-stealing pool", "workers", poolSize)
+		} else {
+			localPool := workerpool.New(poolSize)
+			defer localPool.Close()
+			pool = localPool
+			poolHealth = localPool
+			logger.Info("Using worker pool", "workers", poolSize)
+		}
+	}
+
+	if metricsAddr != "" {
+		metricsServer := metrics.NewServer(metricsAddr, poolHealth, deadlineCheck(ctx))
+		go func() {
+			if serveErr := metricsServer.ListenAndServe(); serveErr != nil && serveErr != http.ErrServerClosed {
+				logger.Error("Metrics server failed", "error", serveErr)
+			}
+		}()
+		defer func() {
+			if closeErr := metricsServer.Close(); closeErr != nil {
+				logger.Debug("Failed to close metrics server", "error", closeErr)
+			}
+		}()
+		logger.Info("Serving metrics", "addr", metricsAddr)
+	}
+
+	// Create calculator, optionally resuming from an on-disk checkpoint log
+	calcOpts := []calculator.Option{calculator.WithProgressReporter(progressReporter)}
+	if checkpointDir != "" {
+		sanitizedCheckpointDir, err := security.SanitizePath(checkpointDir)
+		if err != nil {
+			logger.Error("Invalid checkpoint directory", "error", err)
+			os.Exit(1)
+		}
+		// #nosec G301 -- 0755 is appropriate for directory creation
+		if err := os.MkdirAll(sanitizedCheckpointDir, 0o755); err != nil {
+			logger.Error("Failed to create checkpoint directory", "error", err, "path", sanitizedCheckpointDir)
+			os.Exit(1)
+		}
+
+		checkpointPath := filepath.Join(sanitizedCheckpointDir, "checkpoint.log")
+		checkpointStore, err := calculator.NewFileCheckpointStore(checkpointPath, false)
+		if err != nil {
+			logger.Error("Failed to open checkpoint store", "error", err, "path", checkpointPath)
+			os.Exit(1)
+		}
+		defer func() {
+			if closeErr := checkpointStore.Close(); closeErr != nil {
+				logger.Error("Failed to close checkpoint store", "error", closeErr)
+			}
+		}()
+
+		calcOpts = append(calcOpts, calculator.WithCheckpointStore(checkpointStore))
+		logger.Info("Resuming from checkpoint store if present", "path", checkpointPath)
+	}
+	if benchmarkAll {
+		runBenchmarkAll(ctx, cfg, pool, calcOpts, digits, logger)
+		return
 	}
 
-	// Create calculator
-	calc := calculator.New(cfg, pool)
+	algorithm, err := algorithmFor(algorithmName, cfg, pool, calcOpts)
+	if err != nil {
+		logger.Error("Invalid algorithm", "error", err, "algorithm", algorithmName)
+		os.Exit(1)
+	}
 
 	// Measure execution time
 	startTime := time.Now()
 
 	// Compute pi
-	piStr, err := calc.ComputePi(ctx, digits)
+	piStr, err := algorithm.ComputePi(ctx, digits)
 	if err != nil {
 		logger.Error("Failed to compute pi", "error", err)
 		os.Exit(1)
@@ -194,10 +339,38 @@ func main() {
 	elapsed := time.Since(startTime)
 	logger.Info("Computation complete", "duration", elapsed, "digits_per_second", float64(digits)/elapsed.Seconds())
 
-	// Format output
-	formattedOutput := formatter.FormatPiOutput(int(digits), piStr)
+	if verifyCount > 0 {
+		verifyResult, err := calculator.Verify(ctx, piStr, digits)
+		if err != nil {
+			logger.Error("BBP verification failed to run", "error", err)
+		} else if verifyResult.FirstMismatch != -1 {
+			logger.Error("BBP verification found a mismatch",
+				"hex_position", verifyResult.WindowStart+int64(verifyResult.FirstMismatch),
+				"bbp_digit", verifyResult.BBPDigits[verifyResult.FirstMismatch],
+				"decimal_digit", verifyResult.DecimalDigits[verifyResult.FirstMismatch])
+		} else {
+			logger.Info("BBP verification passed",
+				"window_start", verifyResult.WindowStart,
+				"window_size", len(verifyResult.BBPDigits))
+		}
+	}
 
-	// Sanitize and save file
+	if verifySample {
+		sampleResult, err := calculator.VerifySample(ctx, piStr, digits, verifySampleCount, nil)
+		if err != nil {
+			logger.Error("BBP sample verification failed to run", "error", err)
+		} else if sampleResult.FirstMismatch != -1 {
+			idx := sampleResult.FirstMismatch
+			logger.Error("BBP sample verification found a mismatch",
+				"hex_position", sampleResult.Positions[idx],
+				"bbp_digit", sampleResult.BBPDigits[idx],
+				"decimal_digit", sampleResult.DecimalDigits[idx])
+		} else {
+			logger.Info("BBP sample verification passed", "positions_checked", len(sampleResult.Positions))
+		}
+	}
+
+	// Sanitize the output path before any encoder touches the filesystem.
 	sanitizedPath, err := security.SanitizePath(outputPath)
 	if err != nil {
 		logger.Error("Invalid output path", "error", err)
@@ -213,9 +386,25 @@ func main() {
 		}
 	}
 
-	// #nosec G306 -- 0644 is appropriate for readable output files
-	if err := os.WriteFile(sanitizedPath, []byte(formattedOutput), 0o644); err != nil {
-		logger.Error("Failed to write file", "error", err, "path", sanitizedPath)
+	encoder, err := encoderForFormat(outputFormat, sanitizedPath)
+	if err != nil {
+		logger.Error("Invalid output format", "error", err, "format", outputFormat)
+		os.Exit(1)
+	}
+
+	// #nosec G304 -- sanitizedPath has already been through security.SanitizePath
+	outFile, err := os.Create(sanitizedPath)
+	if err != nil {
+		logger.Error("Failed to create output file", "error", err, "path", sanitizedPath)
+		os.Exit(1)
+	}
+	if err := encoder.Encode(outFile, int(digits), piStr); err != nil {
+		_ = outFile.Close()
+		logger.Error("Failed to encode output", "error", err, "path", sanitizedPath)
+		os.Exit(1)
+	}
+	if err := outFile.Close(); err != nil {
+		logger.Error("Failed to close output file", "error", err, "path", sanitizedPath)
 		os.Exit(1)
 	}
 
@@ -226,3 +415,139 @@ func main() {
 		fmt.Println(piStr)
 	}
 }
+
+// encoderForFormat selects the formatter.Encoder matching -format.
+// sanitizedPath is the already-sanitized primary output path; the paged
+// encoder derives its page directory from it rather than taking a second,
+// unsanitized path flag.
+func encoderForFormat(format, sanitizedPath string) (formatter.Encoder, error) {
+	switch format {
+	case "text", "":
+		return formatter.TextEncoder{}, nil
+	case "json":
+		return formatter.JSONEncoder{}, nil
+	case "binary":
+		return formatter.BinaryEncoder{}, nil
+	case "paged":
+		dir := sanitizedPath + ".pages"
+		sanitizedDir, err := security.SanitizePath(dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid paged output directory: %w", err)
+		}
+		return formatter.PagedEncoder{Dir: sanitizedDir}, nil
+	case "ycruncher":
+		sanitizedYCDPath, err := security.SanitizePath(sanitizedPath + ".ycd")
+		if err != nil {
+			return nil, fmt.Errorf("invalid ycd output path: %w", err)
+		}
+		return formatter.YCruncherEncoder{YCDPath: sanitizedYCDPath}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// algorithmFor selects the calculator.PiAlgorithm matching -algorithm.
+// pool and calcOpts are only meaningful for "chudnovsky" (the only
+// algorithm whose PQT binary splitting and checkpointing can use them);
+// AGM accepts pool but ignores it, and Machin and Ramanujan take no pool
+// at all.
+func algorithmFor(name string, cfg *config.Config, pool calculator.PoolInterface, calcOpts []calculator.Option) (calculator.PiAlgorithm, error) {
+	switch name {
+	case "chudnovsky", "":
+		return calculator.New(cfg, pool, calcOpts...), nil
+	case "agm":
+		return calculator.NewAGMCalculator(cfg, pool), nil
+	case "machin":
+		return calculator.NewMachinCalculator(cfg), nil
+	case "ramanujan":
+		return calculator.NewRamanujanCalculator(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown algorithm %q", name)
+	}
+}
+
+// coordinatorTLSConfig builds a client-side tls.Config presenting the
+// coordinator's own certificate (mutual auth) and verifying workers
+// against workerCAFile.
+func coordinatorTLSConfig(certFile, keyFile, workerCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load coordinator keypair: %w", err)
+	}
+
+	sanitizedCAFile, err := security.SanitizePath(workerCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker CA path: %w", err)
+	}
+	// #nosec G304 -- sanitizedCAFile has already been through security.SanitizePath
+	caPEM, err := os.ReadFile(sanitizedCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read worker CA bundle: %w", err)
+	}
+	workerCAs := x509.NewCertPool()
+	if !workerCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", workerCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      workerCAs,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// deadlineCheck adapts ctx's deadline (set via -deadline, if any) into a
+// metrics.DeadlineCheck: ok is true whenever ctx has no deadline or hasn't
+// passed it yet.
+func deadlineCheck(ctx context.Context) metrics.DeadlineCheck {
+	return func() (bool, time.Duration) {
+		dl, ok := ctx.Deadline()
+		if !ok {
+			return true, 0
+		}
+		remaining := time.Until(dl)
+		return remaining > 0, remaining
+	}
+}
+
+// peakRSSKB returns the process's peak resident set size in kilobytes, as
+// reported by getrusage(2)'s ru_maxrss on Linux.
+func peakRSSKB() int64 {
+	var rusage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &rusage); err != nil {
+		return 0
+	}
+	return rusage.Maxrss
+}
+
+// runBenchmarkAll computes pi to digits with every registered PiAlgorithm
+// in turn and logs wall time, peak RSS, and digits/sec for each, so the
+// quadratically-convergent AGM iteration can be compared against
+// Chudnovsky's binary splitting (and the other reference algorithms) at
+// the same N.
+func runBenchmarkAll(ctx context.Context, cfg *config.Config, pool calculator.PoolInterface, calcOpts []calculator.Option, digits int64, logger *slog.Logger) {
+	algorithms := []calculator.PiAlgorithm{
+		calculator.New(cfg, pool, calcOpts...),
+		calculator.NewAGMCalculator(cfg, pool),
+		calculator.NewMachinCalculator(cfg),
+		calculator.NewRamanujanCalculator(cfg),
+	}
+
+	for _, algorithm := range algorithms {
+		start := time.Now()
+		piStr, err := algorithm.ComputePi(ctx, digits)
+		elapsed := time.Since(start)
+		if err != nil {
+			logger.Error("Benchmark algorithm failed", "algorithm", algorithm.Name(), "error", err)
+			continue
+		}
+
+		logger.Info("Benchmark result",
+			"algorithm", algorithm.Name(),
+			"digits", len(piStr),
+			"duration", elapsed,
+			"digits_per_second", float64(digits)/elapsed.Seconds(),
+			"peak_rss_kb", peakRSSKB(),
+		)
+	}
+}
@@ -0,0 +1,156 @@
+// Command chudnovskyckpt inspects, validates, and prunes the checkpoint log
+// written by cmd/chudnovsky's -checkpoint-dir flag. It operates on the same
+// internal/calculator.FileCheckpointStore a running computation uses, so a
+// "valid" verdict here is exactly what a resumed run would see. export and
+// import convert between that live, append-only WAL and a single portable
+// snapshot file (internal/calculator.SaveCheckpoint/LoadCheckpoint), for
+// archiving a checkpoint or moving it to another machine.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/calculator"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/security"
+)
+
+func main() {
+	args := os.Args[1:]
+	if len(args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	subcommand, dir := args[0], args[1]
+	sanitizedDir, err := security.SanitizePath(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid checkpoint directory: %v\n", err)
+		os.Exit(1)
+	}
+	path := filepath.Join(sanitizedDir, "checkpoint.log")
+
+	store, err := calculator.NewFileCheckpointStore(path, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open checkpoint log %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	defer func() {
+		if closeErr := store.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "failed to close checkpoint log: %v\n", closeErr)
+		}
+	}()
+
+	switch subcommand {
+	case "inspect":
+		inspect(store)
+	case "validate":
+		if !validate(store) {
+			os.Exit(1)
+		}
+	case "prune":
+		prune(store)
+	case "export":
+		if len(args) < 3 {
+			usage()
+			os.Exit(1)
+		}
+		if err := exportSnapshot(store, args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "export failed: %v\n", err)
+			os.Exit(1)
+		}
+	case "import":
+		if len(args) < 3 {
+			usage()
+			os.Exit(1)
+		}
+		if err := importSnapshot(store, args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Println("Usage: chudnovskyckpt <inspect|validate|prune> <checkpoint-dir>")
+	fmt.Println("       chudnovskyckpt <export|import> <checkpoint-dir> <snapshot-file>")
+}
+
+// exportSnapshot writes every range currently in store's index to a single
+// portable snapshot file via calculator.SaveCheckpoint.
+func exportSnapshot(store *calculator.FileCheckpointStore, snapshotPath string) error {
+	manifest := store.Manifest()
+	results := make([]config.Result, 0, len(manifest))
+	for _, entry := range manifest {
+		result, ok := store.Completed(entry.A, entry.B)
+		if !ok {
+			return fmt.Errorf("range [%d,%d) vanished from the index mid-export", entry.A, entry.B)
+		}
+		results = append(results, result)
+	}
+	if err := calculator.SaveCheckpoint(snapshotPath, results, config.Default()); err != nil {
+		return err
+	}
+	fmt.Printf("exported %d range(s) to %s\n", len(results), snapshotPath)
+	return nil
+}
+
+// importSnapshot loads a snapshot written by exportSnapshot/SaveCheckpoint
+// and re-saves each of its chunks into store's WAL, so a running
+// computation resuming from -checkpoint-dir picks them up on its next
+// Completed lookup.
+func importSnapshot(store *calculator.FileCheckpointStore, snapshotPath string) error {
+	results, _, err := calculator.LoadCheckpoint(snapshotPath)
+	if err != nil {
+		return err
+	}
+	for _, r := range results {
+		env := calculator.CheckpointEnvelope{A: r.Range[0], B: r.Range[1], P: r.P, Q: r.Q, T: r.T}
+		if err := store.Save(env); err != nil {
+			return fmt.Errorf("range [%d,%d): %w", r.Range[0], r.Range[1], err)
+		}
+	}
+	fmt.Printf("imported %d range(s) from %s\n", len(results), snapshotPath)
+	return nil
+}
+
+// inspect prints the current manifest: one (a,b,file,hash) line per range
+// the store would hand back on resume.
+func inspect(store *calculator.FileCheckpointStore) {
+	manifest := store.Manifest()
+	fmt.Printf("%d range(s) in checkpoint\n", len(manifest))
+	for _, entry := range manifest {
+		fmt.Printf("[%d,%d) level=%d file=%s crc32=%08x\n", entry.A, entry.B, entry.Level, entry.File, entry.Hash)
+	}
+}
+
+// validate reports any ranges replay found corrupt (checksum mismatch) and
+// returns whether the checkpoint log is clean.
+func validate(store *calculator.FileCheckpointStore) bool {
+	corrupt := store.CorruptRanges()
+	if len(corrupt) == 0 {
+		fmt.Println("OK: all entries passed checksum verification")
+		return true
+	}
+	for _, r := range corrupt {
+		fmt.Printf("CORRUPT: range [%d,%d) failed checksum verification and was discarded\n", r[0], r[1])
+	}
+	return false
+}
+
+// prune rewrites the log down to the current index, dropping corrupt
+// entries and leaf ranges already folded into a compacted parent.
+func prune(store *calculator.FileCheckpointStore) {
+	before := len(store.Manifest())
+	if err := store.Prune(); err != nil {
+		fmt.Fprintf(os.Stderr, "prune failed: %v\n", err)
+		os.Exit(1)
+	}
+	after := len(store.Manifest())
+	fmt.Printf("pruned checkpoint log to %d range(s) (was tracking %d)\n", after, before)
+}
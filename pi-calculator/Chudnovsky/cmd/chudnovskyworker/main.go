@@ -0,0 +1,105 @@
+// Command chudnovskyworker runs the worker side of the distributed PQT
+// subsystem in pkg/rpc: it listens for a coordinator (cmd/chudnovsky run
+// with -workers) and computes whatever [start, end) term ranges it is
+// assigned, via the unmodified calculator.ComputePQTSequential.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/security"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/pkg/rpc"
+)
+
+func main() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	var (
+		addr     string
+		certFile string
+		keyFile  string
+		clientCA string
+	)
+
+	flag.StringVar(&addr, "addr", ":7800", "address to listen for coordinator connections on")
+	flag.StringVar(&certFile, "tls-cert", "", "this worker's TLS certificate (required)")
+	flag.StringVar(&keyFile, "tls-key", "", "this worker's TLS private key (required)")
+	flag.StringVar(&clientCA, "tls-client-ca", "", "CA bundle used to verify the coordinator's client certificate (required, mutual auth)")
+	flag.Parse()
+
+	if certFile == "" || keyFile == "" || clientCA == "" {
+		fmt.Println("Usage: chudnovskyworker -tls-cert <cert> -tls-key <key> -tls-client-ca <ca-bundle> [-addr host:port]")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	tlsConfig, err := workerTLSConfig(certFile, keyFile, clientCA)
+	if err != nil {
+		logger.Error("Failed to load TLS configuration", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		logger.Info("Received interrupt signal, shutting down worker...")
+		cancel()
+	}()
+
+	server, err := rpc.ListenAndServe(ctx, addr, tlsConfig, rpc.NewWorkerService())
+	if err != nil {
+		logger.Error("Failed to start worker listener", "error", err, "addr", addr)
+		os.Exit(1)
+	}
+	defer func() {
+		if closeErr := server.Close(); closeErr != nil {
+			logger.Debug("Failed to close worker listener", "error", closeErr)
+		}
+	}()
+
+	logger.Info("Worker listening", "addr", addr)
+	<-ctx.Done()
+}
+
+// workerTLSConfig builds a server-side tls.Config requiring and verifying
+// the coordinator's client certificate against clientCAFile (mutual
+// authentication).
+func workerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load worker keypair: %w", err)
+	}
+
+	sanitizedCAFile, err := security.SanitizePath(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client CA path: %w", err)
+	}
+	// #nosec G304 -- sanitizedCAFile has already been through security.SanitizePath
+	caPEM, err := os.ReadFile(sanitizedCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA bundle: %w", err)
+	}
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", clientCAFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
@@ -0,0 +1,94 @@
+// Command verifypi (VerifyComputedPi) cross-checks a π output file produced
+// by cmd/chudnovsky against hexadecimal digits computed independently via
+// the Bailey–Borwein–Plouffe formula. Unlike compare_pi.go, it needs no
+// second "known-correct" reference file. It checks a trailing window that
+// stops a few hex digits short of the end (see calculator.Verify), and any
+// disagreement inside that window is reported.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/calculator"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/security"
+)
+
+func main() {
+	var digits int64
+	flag.Int64Var(&digits, "digits", 0, "number of decimal digits in the file (0 = count them from the file)")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		fmt.Println("Usage: verifypi [-digits N] <pi_output_file>")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	sanitizedPath, err := security.SanitizePath(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid path: %v\n", err)
+		os.Exit(1)
+	}
+
+	// #nosec G304 -- sanitizedPath has already been through security.SanitizePath
+	content, err := os.ReadFile(sanitizedPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", sanitizedPath, err)
+		os.Exit(1)
+	}
+
+	piStr, fractionLen := extractPiString(string(content))
+	if digits <= 0 {
+		digits = int64(fractionLen)
+	}
+
+	result, err := calculator.Verify(context.Background(), piStr, digits)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "verification failed to run: %v\n", err)
+		os.Exit(1)
+	}
+
+	if result.FirstMismatch != -1 {
+		fmt.Printf("MISMATCH at hex position %d: bbp=%x decimal=%x\n",
+			result.WindowStart+int64(result.FirstMismatch),
+			result.BBPDigits[result.FirstMismatch], result.DecimalDigits[result.FirstMismatch])
+		os.Exit(1)
+	}
+
+	fmt.Printf("OK: %d trailing hex digits (from position %d) match an independent BBP computation\n",
+		len(result.BBPDigits), result.WindowStart)
+}
+
+// extractPiString reconstructs a "3.<fraction>" decimal string from a
+// cmd/chudnovsky text output file (header lines, then "3.", then digit
+// lines) and returns it alongside the number of fractional digits found.
+func extractPiString(content string) (piStr string, fractionDigits int) {
+	lines := strings.Split(content, "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "3." {
+			start = i + 1
+			break
+		}
+	}
+	if start < 0 {
+		return "", 0
+	}
+
+	var fraction strings.Builder
+	for _, line := range lines[start:] {
+		for _, r := range line {
+			if r >= '0' && r <= '9' {
+				fraction.WriteRune(r)
+			}
+		}
+	}
+
+	return "3." + fraction.String(), fraction.Len()
+}
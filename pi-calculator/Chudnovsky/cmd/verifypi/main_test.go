@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+// FuzzExtractPiString checks that extractPiString never panics on
+// arbitrary file content and that fractionDigits always matches the
+// number of digits actually returned in piStr's fractional part.
+func FuzzExtractPiString(f *testing.F) {
+	seeds := []string{
+		"1000 Digits of Pi\ncollected by Vu Hung\n\n3.\n14159\n26535\n",
+		"",
+		"3.",
+		"no header at all",
+		"3.\nabc123def456\n",
+		"3.\n\x00\x00\n",
+		"3.\n3.\n12345\n",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		piStr, fractionDigits := extractPiString(content)
+		if piStr == "" {
+			if fractionDigits != 0 {
+				t.Errorf("extractPiString(%q) = (%q, %d), expected fractionDigits 0 for empty piStr", content, piStr, fractionDigits)
+			}
+			return
+		}
+		if len(piStr) < 2 || piStr[:2] != "3." {
+			t.Errorf("extractPiString(%q) = (%q, %d), expected piStr to start with \"3.\"", content, piStr, fractionDigits)
+		}
+		if len(piStr)-2 != fractionDigits {
+			t.Errorf("extractPiString(%q) = (%q, %d), fractionDigits doesn't match piStr's fraction length", content, piStr, fractionDigits)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+// Package arith abstracts the arbitrary-precision arithmetic used to
+// assemble a final π value from the Chudnovsky binary-splitting result
+// (pi = 426880 * sqrt(10005) * Q / T), so that step can run against
+// math/big or against a faster cgo-backed library without the calculator
+// package caring which. The P/Q/T binary-splitting recursion itself stays
+// on math/big directly and is untouched by the choice of Backend here.
+//
+// That recursion, not this finalize step, is where the vast majority of
+// multiplication time goes at the multi-million-digit sizes GMP's
+// sub-quadratic multiplication would actually matter for; this seam alone
+// does not give ComputePi an order-of-magnitude speedup. It's groundwork
+// for a future change that threads Backend (or an equivalent Int-level
+// interface) through CombineResults itself.
+package arith
+
+import "math/big"
+
+// Int is an opaque arbitrary-precision integer handle produced by a
+// Backend. Callers pass Int values between Backend methods; they never
+// inspect the underlying representation.
+type Int interface {
+	arithInt()
+}
+
+// Float is an opaque arbitrary-precision floating-point handle produced
+// by a Backend.
+type Float interface {
+	arithFloat()
+}
+
+// Backend performs the arithmetic needed to turn a binary-splitting
+// result into a decimal π string: one Sqrt, a couple of Mul/Quo, and a
+// Text conversion, regardless of digit count. It is the seam a cgo-backed
+// GMP/MPFR Backend (build tag chudnovsky_gmp) plugs into instead of
+// math/big for that handful of operations; the pure Go BigBackend is the
+// default and requires no cgo. Because the binary-splitting recursion
+// that dominates runtime at scale doesn't go through Backend, swapping
+// backends here changes only this small fixed-cost tail, not overall
+// ComputePi throughput.
+type Backend interface {
+	// IntFromBig wraps an existing *big.Int (as produced by the
+	// binary-splitting combiner) into this backend's Int handle.
+	IntFromBig(v *big.Int) Int
+
+	// FloatFromInt converts an Int handle to a Float handle with prec
+	// bits of precision.
+	FloatFromInt(v Int, prec uint) Float
+
+	// NewFloat creates a Float handle holding the int64 value n with
+	// prec bits of precision.
+	NewFloat(n int64, prec uint) Float
+
+	Add(a, b Float) Float
+	Mul(a, b Float) Float
+	Quo(a, b Float) Float
+	Neg(a Float) Float
+	Sqrt(a Float) Float
+
+	// Text renders a Float as a fixed-point decimal string with
+	// fractionDigits digits after the point, matching the output of
+	// fmt.Sprintf("%.*f", fractionDigits, v) for a math/big.Float v.
+	Text(a Float, fractionDigits int) string
+}
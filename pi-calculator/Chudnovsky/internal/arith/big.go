@@ -0,0 +1,66 @@
+package arith
+
+import "math/big"
+
+// bigInt and bigFloat wrap math/big's types so BigBackend can satisfy the
+// opaque Int/Float handles without exposing *big.Int/*big.Float directly.
+type bigInt struct{ v *big.Int }
+type bigFloat struct{ v *big.Float }
+
+func (bigInt) arithInt()     {}
+func (bigFloat) arithFloat() {}
+
+// BigBackend implements Backend using math/big. It is the default backend
+// and requires no cgo.
+type BigBackend struct{}
+
+// IntFromBig implements Backend.
+func (BigBackend) IntFromBig(v *big.Int) Int {
+	return bigInt{v: v}
+}
+
+// FloatFromInt implements Backend.
+func (BigBackend) FloatFromInt(v Int, prec uint) Float {
+	return bigFloat{v: new(big.Float).SetPrec(prec).SetInt(v.(bigInt).v)}
+}
+
+// NewFloat implements Backend.
+func (BigBackend) NewFloat(n int64, prec uint) Float {
+	return bigFloat{v: new(big.Float).SetPrec(prec).SetInt64(n)}
+}
+
+// Add implements Backend.
+func (BigBackend) Add(a, b Float) Float {
+	af, bf := a.(bigFloat), b.(bigFloat)
+	return bigFloat{v: new(big.Float).SetPrec(af.v.Prec()).Add(af.v, bf.v)}
+}
+
+// Mul implements Backend.
+func (BigBackend) Mul(a, b Float) Float {
+	af, bf := a.(bigFloat), b.(bigFloat)
+	return bigFloat{v: new(big.Float).SetPrec(af.v.Prec()).Mul(af.v, bf.v)}
+}
+
+// Quo implements Backend.
+func (BigBackend) Quo(a, b Float) Float {
+	af, bf := a.(bigFloat), b.(bigFloat)
+	return bigFloat{v: new(big.Float).SetPrec(af.v.Prec()).Quo(af.v, bf.v)}
+}
+
+// Neg implements Backend.
+func (BigBackend) Neg(a Float) Float {
+	af := a.(bigFloat)
+	return bigFloat{v: new(big.Float).SetPrec(af.v.Prec()).Neg(af.v)}
+}
+
+// Sqrt implements Backend.
+func (BigBackend) Sqrt(a Float) Float {
+	af := a.(bigFloat)
+	return bigFloat{v: new(big.Float).SetPrec(af.v.Prec()).Sqrt(af.v)}
+}
+
+// Text implements Backend.
+func (BigBackend) Text(a Float, fractionDigits int) string {
+	af := a.(bigFloat)
+	return af.v.Text('f', fractionDigits)
+}
@@ -0,0 +1,39 @@
+package arith
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestBigBackend_Arithmetic(t *testing.T) {
+	var b BigBackend
+	const prec = 256
+
+	two := b.IntFromBig(big.NewInt(2))
+	fTwo := b.FloatFromInt(two, prec)
+	four := b.Mul(fTwo, fTwo)
+	if got := b.Text(four, 0); got != "4" {
+		t.Errorf("2*2: expected 4, got %s", got)
+	}
+
+	sum := b.Add(fTwo, fTwo)
+	if got := b.Text(sum, 0); got != "4" {
+		t.Errorf("2+2: expected 4, got %s", got)
+	}
+
+	quo := b.Quo(four, fTwo)
+	if got := b.Text(quo, 0); got != "2" {
+		t.Errorf("4/2: expected 2, got %s", got)
+	}
+
+	neg := b.Neg(fTwo)
+	if got := b.Text(neg, 0); got != "-2" {
+		t.Errorf("-2: expected -2, got %s", got)
+	}
+
+	four64 := b.NewFloat(4, prec)
+	sqrt := b.Sqrt(four64)
+	if got := b.Text(sqrt, 0); got != "2" {
+		t.Errorf("sqrt(4): expected 2, got %s", got)
+	}
+}
@@ -0,0 +1,119 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// AGMCalculator computes π via the Gauss-Legendre arithmetic-geometric-mean
+// iteration, which is quadratically convergent: each iteration roughly
+// doubles the number of correct digits. It exists mainly as an
+// algorithmically independent cross-check against Chudnovsky for the same
+// digit count (see cmd/chudnovsky's -benchmark-all), not as the fastest
+// path to a given N - Chudnovsky's ~14 digits/term with cheap big.Int
+// binary splitting still wins in practice.
+//
+// Unlike Calculator's P/Q/T binary splitting, AGM's four running values
+// (a, b, t, p) form a strict sequential dependency chain across
+// iterations, so there's no subrange of iterations to hand to
+// PoolInterface the way ComputePQT hands off [a,b) term ranges: PQT's
+// Submit/Result shape is built around aggregating many independent
+// big.Int leaf terms, and AGM has no such independent leaves. pool is
+// accepted and stored, matching Calculator's constructor (Machin and
+// Ramanujan take no pool), but ComputePi never uses it. The one
+// independent pair of operations per step (the b_{n+1} square root and
+// the t_{n+1} update both only depend on a_n, b_n, a_{n+1}) is computed
+// concurrently in agmStep via a plain goroutine rather than through
+// PoolInterface.
+type AGMCalculator struct {
+	cfg  *config.Config
+	pool PoolInterface
+}
+
+// NewAGMCalculator creates an AGM-based π calculator. pool may be nil.
+func NewAGMCalculator(cfg *config.Config, pool PoolInterface) *AGMCalculator {
+	return &AGMCalculator{cfg: cfg, pool: pool}
+}
+
+// Name implements PiAlgorithm.
+func (c *AGMCalculator) Name() string { return "agm" }
+
+// ComputePi implements PiAlgorithm using the Gauss-Legendre AGM recurrence:
+//
+//	a0=1, b0=1/sqrt(2), t0=1/4, p0=1
+//	a_{n+1} = (a_n+b_n)/2
+//	b_{n+1} = sqrt(a_n*b_n)
+//	t_{n+1} = t_n - p_n*(a_n-a_{n+1})^2
+//	p_{n+1} = 2*p_n
+//	pi ~= (a+b)^2 / (4t)
+//
+// Precision is set to bitsForDigits(digits) bits (guardBits of them
+// headroom) on every big.Float, and the loop runs ceil(log2(digits))+2
+// iterations.
+func (c *AGMCalculator) ComputePi(ctx context.Context, digits int64) (string, error) {
+	if digits < 1 {
+		return "", fmt.Errorf("digits must be at least 1, got %d", digits)
+	}
+	if digits > c.cfg.MaxDigits {
+		return "", fmt.Errorf("digits exceeds maximum allowed (%d), got %d", c.cfg.MaxDigits, digits)
+	}
+
+	prec := bitsForDigits(digits)
+	iterations := int(math.Ceil(math.Log2(float64(digits)))) + 2
+
+	one := new(big.Float).SetPrec(prec).SetInt64(1)
+	two := new(big.Float).SetPrec(prec).SetInt64(2)
+	four := new(big.Float).SetPrec(prec).SetInt64(4)
+
+	a := new(big.Float).SetPrec(prec).Set(one)
+	half := new(big.Float).SetPrec(prec).Quo(one, two)
+	b := new(big.Float).SetPrec(prec).Sqrt(half)
+	t := new(big.Float).SetPrec(prec).Quo(one, four)
+	p := new(big.Float).SetPrec(prec).Set(one)
+
+	for i := 0; i < iterations; i++ {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		default:
+		}
+
+		aNext := new(big.Float).SetPrec(prec).Add(a, b)
+		aNext.Quo(aNext, two)
+
+		bNext, pDiffSq := agmStep(prec, a, b, aNext, p)
+
+		t.Sub(t, pDiffSq)
+		p.Mul(p, two)
+		a, b = aNext, bNext
+	}
+
+	apb := new(big.Float).SetPrec(prec).Add(a, b)
+	apbSq := new(big.Float).SetPrec(prec).Mul(apb, apb)
+	fourT := new(big.Float).SetPrec(prec).Mul(t, four)
+	pi := new(big.Float).SetPrec(prec).Quo(apbSq, fourT)
+
+	return pi.Text('f', int(digits)), nil
+}
+
+// agmStep computes b_{n+1} = sqrt(a*b) and p_n*(a-a_next)^2 concurrently:
+// both depend only on a, b, aNext, and p from before this step, not on
+// each other, so they're independent work within one AGM iteration.
+func agmStep(prec uint, a, b, aNext, p *big.Float) (bNext, pDiffSq *big.Float) {
+	bNextCh := make(chan *big.Float, 1)
+	go func() {
+		prod := new(big.Float).SetPrec(prec).Mul(a, b)
+		bNextCh <- new(big.Float).SetPrec(prec).Sqrt(prod)
+	}()
+
+	diff := new(big.Float).SetPrec(prec).Sub(a, aNext)
+	diffSq := new(big.Float).SetPrec(prec).Mul(diff, diff)
+	pDiffSq = new(big.Float).SetPrec(prec).Mul(p, diffSq)
+
+	bNext = <-bNextCh
+	return bNext, pDiffSq
+}
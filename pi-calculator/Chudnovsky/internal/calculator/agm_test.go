@@ -0,0 +1,56 @@
+package calculator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// knownPiPrefix is a reference prefix used to sanity-check every
+// PiAlgorithm implementation against a well-known value of pi.
+const knownPiPrefix = "3.14159265358979323846"
+
+func TestAGMCalculator_ComputePi(t *testing.T) {
+	cfg := config.Default()
+	calc := NewAGMCalculator(cfg, nil)
+
+	piStr, err := calc.ComputePi(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(piStr, knownPiPrefix) {
+		t.Errorf("Expected prefix %q, got %q", knownPiPrefix, piStr)
+	}
+}
+
+func TestAGMCalculator_Name(t *testing.T) {
+	calc := NewAGMCalculator(config.Default(), nil)
+	if calc.Name() != "agm" {
+		t.Errorf("Expected name 'agm', got %q", calc.Name())
+	}
+}
+
+func TestAGMCalculator_InvalidInput(t *testing.T) {
+	cfg := config.Default()
+	calc := NewAGMCalculator(cfg, nil)
+	ctx := context.Background()
+
+	if _, err := calc.ComputePi(ctx, 0); err == nil {
+		t.Error("Expected error for zero digits")
+	}
+	if _, err := calc.ComputePi(ctx, cfg.MaxDigits+1); err == nil {
+		t.Error("Expected error for exceeding max digits")
+	}
+}
+
+func TestAGMCalculator_ContextCancellation(t *testing.T) {
+	calc := NewAGMCalculator(config.Default(), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := calc.ComputePi(ctx, 1000); err == nil {
+		t.Error("Expected error due to context cancellation")
+	}
+}
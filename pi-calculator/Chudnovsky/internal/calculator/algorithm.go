@@ -0,0 +1,37 @@
+package calculator
+
+import (
+	"context"
+	"math"
+)
+
+// PiAlgorithm is implemented by every π-computing strategy in this package,
+// so callers (notably the CLI's -algorithm and -benchmark-all flags) can
+// select and compare them uniformly. Chudnovsky (via Calculator itself)
+// remains the default and fastest algorithm; AGM, Machin, and Ramanujan
+// exist mainly as algorithmically independent cross-checks and as
+// reference points for -benchmark-all.
+type PiAlgorithm interface {
+	// Name identifies the algorithm, e.g. for -algorithm and benchmark
+	// reports ("chudnovsky", "agm", "machin", "ramanujan").
+	Name() string
+
+	// ComputePi computes π to the given number of decimal digits.
+	ComputePi(ctx context.Context, digits int64) (string, error)
+}
+
+// Name implements PiAlgorithm.
+func (c *Calculator) Name() string { return "chudnovsky" }
+
+// guardBits is the number of extra bits of precision carried beyond the
+// ceil(digits*log2(10)) strictly required to represent the requested
+// decimal digits, absorbing rounding error accumulated across an
+// algorithm's iterations before the final Text conversion.
+const guardBits = 32
+
+// bitsForDigits returns the big.Float precision needed to safely carry
+// digits decimal digits, per the guardBits rationale above.
+func bitsForDigits(digits int64) uint {
+	const log2Of10 = 3.321928094887362
+	return uint(math.Ceil(float64(digits)*log2Of10)) + guardBits
+}
@@ -0,0 +1,23 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+)
+
+// Compile-time assertions that every algorithm implements PiAlgorithm.
+var (
+	_ PiAlgorithm = (*Calculator)(nil)
+	_ PiAlgorithm = (*AGMCalculator)(nil)
+	_ PiAlgorithm = (*MachinCalculator)(nil)
+	_ PiAlgorithm = (*RamanujanCalculator)(nil)
+)
+
+func TestBitsForDigits(t *testing.T) {
+	got := bitsForDigits(1000)
+	bits := float64(1000) * 3.321928094887362
+	want := uint(math.Ceil(bits)) + guardBits
+	if got != want {
+		t.Errorf("Expected %d bits, got %d", want, got)
+	}
+}
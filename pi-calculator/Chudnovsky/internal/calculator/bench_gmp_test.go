@@ -0,0 +1,21 @@
+//go:build chudnovsky_gmp
+
+package calculator
+
+import (
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/arith"
+)
+
+func BenchmarkComputePi_GMPBackend_10kDigits(b *testing.B) {
+	benchComputePiDigitsPerSec(b, arith.GMPBackend{}, 10_000)
+}
+
+func BenchmarkComputePi_GMPBackend_100kDigits(b *testing.B) {
+	benchComputePiDigitsPerSec(b, arith.GMPBackend{}, 100_000)
+}
+
+func BenchmarkComputePi_GMPBackend_1MDigits(b *testing.B) {
+	benchComputePiDigitsPerSec(b, arith.GMPBackend{}, 1_000_000)
+}
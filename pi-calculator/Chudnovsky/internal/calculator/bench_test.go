@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/arith"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/workerpool"
 )
@@ -41,6 +42,46 @@ func BenchmarkComputePQTParallel(b *testing.B) {
 	b.Logf("Execution time: %v, Avg: %v per iteration", elapsed, elapsed/time.Duration(b.N))
 }
 
+// benchComputePiDigitsPerSec runs a full ComputePi at the given digit
+// count under the given arith.Backend and reports digits/sec. Backend
+// only covers ComputePi's fixed-cost finalize step, not the P/Q/T
+// binary-splitting recursion that dominates runtime at these sizes, so
+// BigBackend and a cgo-backed GMP/MPFR backend (built with
+// -tags chudnovsky_gmp) are expected to report close to the same
+// digits/sec here - this isn't a regression, it's the seam's current
+// scope.
+func benchComputePiDigitsPerSec(b *testing.B, backend arith.Backend, digits int64) {
+	cfg := config.Default()
+	pool := workerpool.New(0)
+	defer pool.Close()
+	calc := New(cfg, pool, WithArithBackend(backend))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	start := time.Now()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := calc.ComputePi(ctx, digits); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	elapsed := time.Since(start)
+	b.ReportMetric(float64(digits)*float64(b.N)/elapsed.Seconds(), "digits/sec")
+}
+
+func BenchmarkComputePi_BigBackend_10kDigits(b *testing.B) {
+	benchComputePiDigitsPerSec(b, arith.BigBackend{}, 10_000)
+}
+
+func BenchmarkComputePi_BigBackend_100kDigits(b *testing.B) {
+	benchComputePiDigitsPerSec(b, arith.BigBackend{}, 100_000)
+}
+
+func BenchmarkComputePi_BigBackend_1MDigits(b *testing.B) {
+	benchComputePiDigitsPerSec(b, arith.BigBackend{}, 1_000_000)
+}
+
 func BenchmarkCombineResults(b *testing.B) {
 	results := make([]config.Result, 10)
 	for i := range results {
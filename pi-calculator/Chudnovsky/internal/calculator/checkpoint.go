@@ -0,0 +1,427 @@
+package calculator
+
+import (
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// CheckpointEnvelope wraps a completed [A,B) subrange of the binary-splitting
+// tree together with its aggregated P, Q, T values so it can be persisted
+// and later skipped on resume.
+type CheckpointEnvelope struct {
+	A, B     int64
+	P, Q, T  *big.Int
+	Level    int
+	Checksum uint32
+}
+
+// checksumFor computes a CRC32 (IEEE) checksum over the fields of env that
+// identify its content, so replay can detect an envelope that was only
+// partially written (e.g. a crash mid-fsync) instead of trusting whatever
+// gob/JSON happens to decode. Checksum itself is excluded.
+func checksumFor(env CheckpointEnvelope) uint32 {
+	h := crc32.NewIEEE()
+	fmt.Fprintf(h, "%d:%d:%d:%s:%s:%s", env.A, env.B, env.Level, bigIntString(env.P), bigIntString(env.Q), bigIntString(env.T))
+	return h.Sum32()
+}
+
+// bigIntString renders a possibly-nil *big.Int deterministically so
+// checksumFor doesn't panic or vary on a nil field.
+func bigIntString(v *big.Int) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+// rangeKey identifies a subrange for lookup in the in-memory index.
+type rangeKey struct {
+	A, B int64
+}
+
+// CheckpointStore persists completed subrange results so a long-running
+// computation can resume after interruption instead of starting over.
+type CheckpointStore interface {
+	// Completed reports the aggregated result for [a,b) if it was already
+	// persisted by a previous run.
+	Completed(a, b int64) (config.Result, bool)
+
+	// Save persists a newly completed subrange. Implementations should
+	// fsync before returning so a crash immediately after Save cannot lose
+	// the entry.
+	Save(env CheckpointEnvelope) error
+
+	// Close flushes and releases any underlying resources.
+	Close() error
+}
+
+// FileCheckpointStore is a CheckpointStore backed by an append-only
+// write-ahead log on disk. Entries are gob-encoded by default; JSON is
+// supported as an optional, more debuggable on-disk format.
+type FileCheckpointStore struct {
+	mu      sync.Mutex
+	file    *os.File
+	path    string
+	useJSON bool
+	enc     *gob.Encoder
+	jsonEnc *json.Encoder
+	index   map[rangeKey]CheckpointEnvelope
+	corrupt []rangeKey
+}
+
+// NewFileCheckpointStore opens (or creates) the write-ahead log at path and
+// replays any existing envelopes into memory. Pass useJSON=true to encode
+// new entries as JSON for debuggability instead of the more compact gob
+// format; existing stores are always decoded using the format they were
+// written with.
+func NewFileCheckpointStore(path string, useJSON bool) (*FileCheckpointStore, error) {
+	// #nosec G304 -- path is operator-controlled via --checkpoint-dir
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint log: %w", err)
+	}
+
+	store := &FileCheckpointStore{
+		file:    f,
+		path:    path,
+		useJSON: useJSON,
+		index:   make(map[rangeKey]CheckpointEnvelope),
+	}
+
+	if err := store.replay(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to seek to end of checkpoint log: %w", err)
+	}
+
+	if useJSON {
+		store.jsonEnc = json.NewEncoder(f)
+	} else {
+		store.enc = gob.NewEncoder(f)
+	}
+
+	return store, nil
+}
+
+// replay reads every envelope already present in the log into the
+// in-memory index so Completed can answer without touching disk again.
+// Corruption shows up in two different ways, both recorded in s.corrupt:
+// an entry that decodes successfully but whose Checksum doesn't match its
+// content (e.g. a torn write from a crash mid-fsync, which a gob/JSON
+// record can still decode with garbage values), handled by indexIfValid;
+// and an entry that fails to decode at all (e.g. a torn length prefix),
+// which is indistinguishable from end-of-log to the decoder itself, so it
+// must be treated as corruption rather than silently swallowed. Since
+// neither format supports resyncing past a corrupted record, replay stops
+// at the first undecodable entry; anything appended after it in the log is
+// lost along with it.
+func (s *FileCheckpointStore) replay() error {
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of checkpoint log: %w", err)
+	}
+
+	if s.useJSON {
+		dec := json.NewDecoder(s.file)
+		for {
+			var env CheckpointEnvelope
+			if err := dec.Decode(&env); err != nil {
+				if err != io.EOF {
+					s.recordUndecodable()
+				}
+				break
+			}
+			s.indexIfValid(env)
+		}
+		return nil
+	}
+
+	dec := gob.NewDecoder(s.file)
+	for {
+		var env CheckpointEnvelope
+		if err := dec.Decode(&env); err != nil {
+			if err != io.EOF {
+				s.recordUndecodable()
+			}
+			break
+		}
+		s.indexIfValid(env)
+	}
+	return nil
+}
+
+// undecodableRangeKey is the sentinel rangeKey recordUndecodable appends to
+// s.corrupt for a record that failed to decode at all, since there's no
+// (A,B) to recover from bytes that never became a CheckpointEnvelope.
+// CorruptRanges surfacing this sentinel is the signal an operator should
+// treat the log as truncated rather than complete, even though Manifest
+// still lists every range that did decode.
+var undecodableRangeKey = rangeKey{A: -1, B: -1}
+
+// recordUndecodable records a record that failed to decode at all as
+// corrupt, distinct from indexIfValid's checksum-mismatch case.
+func (s *FileCheckpointStore) recordUndecodable() {
+	s.corrupt = append(s.corrupt, undecodableRangeKey)
+}
+
+// indexIfValid admits env into the in-memory index if its checksum
+// matches, otherwise records its key as corrupt and discards it.
+func (s *FileCheckpointStore) indexIfValid(env CheckpointEnvelope) {
+	key := rangeKey{env.A, env.B}
+	if env.Checksum != checksumFor(env) {
+		s.corrupt = append(s.corrupt, key)
+		return
+	}
+	s.index[key] = env
+}
+
+// Completed implements CheckpointStore.
+func (s *FileCheckpointStore) Completed(a, b int64) (config.Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env, ok := s.index[rangeKey{a, b}]
+	if !ok {
+		return config.Result{}, false
+	}
+	return config.Result{P: env.P, Q: env.Q, T: env.T, Range: [2]int64{a, b}}, true
+}
+
+// Save implements CheckpointStore. It appends the envelope to the WAL,
+// fsyncs so the entry survives a crash, updates the in-memory index, and
+// opportunistically compacts adjacent sibling ranges once both halves of a
+// parent node are present, cascading as far up the tree as newly-complete
+// pairs allow, so the in-memory index stays O(log N) rather than O(N).
+// The on-disk log keeps every appended envelope until Prune rewrites it.
+func (s *FileCheckpointStore) Save(env CheckpointEnvelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	env.Checksum = checksumFor(env)
+	if err := s.appendLocked(env); err != nil {
+		return err
+	}
+	s.index[rangeKey{env.A, env.B}] = env
+
+	s.compactLocked(env)
+	return nil
+}
+
+func (s *FileCheckpointStore) appendLocked(env CheckpointEnvelope) error {
+	var err error
+	if s.useJSON {
+		err = s.jsonEnc.Encode(env)
+	} else {
+		err = s.enc.Encode(env)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append checkpoint envelope: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// compactLocked merges env with its sibling range, if the sibling is
+// already complete, into a single parent envelope, evicts both children
+// from the in-memory index now that the parent covers them, and recurses
+// on the new parent so a merge cascades as far up the tree as the
+// currently-completed ranges allow (e.g. completing the last of four
+// leaves folds all the way up to their shared grandparent in one Save
+// call). The merged envelope is appended to the log on the way up; the
+// children's own envelopes are left in the log (appends are immutable)
+// but are no longer reachable from the index, so Prune is what actually
+// drops them on disk.
+func (s *FileCheckpointStore) compactLocked(env CheckpointEnvelope) {
+	width := env.B - env.A
+	var sibling rangeKey
+	var parent rangeKey
+	if env.A%(2*width) == 0 {
+		sibling = rangeKey{env.B, env.B + width}
+		parent = rangeKey{env.A, env.B + width}
+	} else {
+		sibling = rangeKey{env.A - width, env.A}
+		parent = rangeKey{env.A - width, env.B}
+	}
+
+	sib, ok := s.index[sibling]
+	if !ok {
+		return
+	}
+	if _, already := s.index[parent]; already {
+		return
+	}
+
+	var left, right CheckpointEnvelope
+	if sibling.A < env.A {
+		left, right = sib, env
+	} else {
+		left, right = env, sib
+	}
+
+	p, q, t := CombineResults([]config.Result{
+		{P: left.P, Q: left.Q, T: left.T},
+		{P: right.P, Q: right.Q, T: right.T},
+	})
+	merged := CheckpointEnvelope{A: parent.A, B: parent.B, P: p, Q: q, T: t, Level: env.Level - 1}
+	merged.Checksum = checksumFor(merged)
+	if err := s.appendLocked(merged); err != nil {
+		return
+	}
+	delete(s.index, rangeKey{env.A, env.B})
+	delete(s.index, sibling)
+	s.index[parent] = merged
+
+	s.compactLocked(merged)
+}
+
+// Close implements CheckpointStore.
+func (s *FileCheckpointStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// ManifestEntry describes one range currently held in a FileCheckpointStore's
+// index, for the chudnovskyckpt inspect/validate subcommands. There's no
+// per-node file to name here (all ranges share one WAL), so File is always
+// the store's log path; Hash is the same CRC32 verified on replay.
+type ManifestEntry struct {
+	A, B  int64
+	Level int
+	File  string
+	Hash  uint32
+}
+
+// Manifest lists every range currently present in the index, sorted by
+// start offset, so a chudnovskyckpt inspect/validate run has something
+// stable to print or compare against.
+func (s *FileCheckpointStore) Manifest() []ManifestEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]ManifestEntry, 0, len(s.index))
+	for key, env := range s.index {
+		entries = append(entries, ManifestEntry{A: key.A, B: key.B, Level: env.Level, File: s.path, Hash: env.Checksum})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].A < entries[j].A })
+	return entries
+}
+
+// CorruptRanges reports the (a,b) keys of entries replay found with a
+// mismatching checksum and discarded, plus a (-1,-1) entry for each
+// record replay could not decode at all.
+func (s *FileCheckpointStore) CorruptRanges() [][2]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ranges := make([][2]int64, len(s.corrupt))
+	for i, key := range s.corrupt {
+		ranges[i] = [2]int64{key.A, key.B}
+	}
+	return ranges
+}
+
+// Prune rewrites the write-ahead log so it contains exactly the current
+// index, one envelope per range, dropping both corrupt entries and the
+// now-redundant leaf envelopes that compactLocked has already folded into a
+// parent range. This trades the O(log N) incremental-append guarantee for a
+// one-off O(N) rewrite, so it's meant to be run interactively (via
+// chudnovskyckpt prune) between runs rather than on the hot Save path.
+func (s *FileCheckpointStore) Prune() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmpPath := s.path + ".prune.tmp"
+	// #nosec G304 -- tmpPath is derived from the operator-controlled, already-sanitized store path
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create pruned checkpoint log: %w", err)
+	}
+
+	var enc *gob.Encoder
+	var jsonEnc *json.Encoder
+	if s.useJSON {
+		jsonEnc = json.NewEncoder(tmp)
+	} else {
+		enc = gob.NewEncoder(tmp)
+	}
+	for _, env := range s.index {
+		var encErr error
+		if s.useJSON {
+			encErr = jsonEnc.Encode(env)
+		} else {
+			encErr = enc.Encode(env)
+		}
+		if encErr != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+			return fmt.Errorf("failed to write pruned checkpoint entry: %w", encErr)
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync pruned checkpoint log: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to close pruned checkpoint log: %w", err)
+	}
+
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close checkpoint log before replacing it: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint log with pruned copy: %w", err)
+	}
+
+	// #nosec G304 -- s.path is the same operator-controlled, already-sanitized path the store was opened with
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen pruned checkpoint log: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to seek to end of pruned checkpoint log: %w", err)
+	}
+	s.file = f
+	s.corrupt = nil
+	if s.useJSON {
+		s.jsonEnc = json.NewEncoder(f)
+	} else {
+		s.enc = gob.NewEncoder(f)
+	}
+	return nil
+}
+
+// checkpointContextKey is an unexported type so values stored via
+// WithCheckpointStore cannot collide with keys from other packages.
+type checkpointContextKey struct{}
+
+// withCheckpointContext returns a copy of ctx carrying store so ComputePQT
+// can consult it while recursing without widening the function's signature.
+func withCheckpointContext(ctx context.Context, store CheckpointStore) context.Context {
+	if store == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, checkpointContextKey{}, store)
+}
+
+// checkpointStoreFromContext retrieves the store installed by
+// WithCheckpointStore, if any.
+func checkpointStoreFromContext(ctx context.Context) CheckpointStore {
+	store, _ := ctx.Value(checkpointContextKey{}).(CheckpointStore)
+	return store
+}
@@ -0,0 +1,279 @@
+package calculator
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+func TestFileCheckpointStore_SaveAndResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := store.Save(CheckpointEnvelope{A: 0, B: 1, P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Unexpected error saving checkpoint: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Unexpected error closing store: %v", err)
+	}
+
+	resumed, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	defer resumed.Close()
+
+	result, ok := resumed.Completed(0, 1)
+	if !ok {
+		t.Fatal("Expected previously saved range to be reported completed")
+	}
+	if result.P.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("Expected P=1, got %s", result.P.String())
+	}
+}
+
+func TestFileCheckpointStore_JSONFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.json")
+
+	store, err := NewFileCheckpointStore(path, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Save(CheckpointEnvelope{A: 2, B: 3, P: big.NewInt(5), Q: big.NewInt(7), T: big.NewInt(11)}); err != nil {
+		t.Fatalf("Unexpected error saving checkpoint: %v", err)
+	}
+
+	result, ok := store.Completed(2, 3)
+	if !ok || result.Q.Cmp(big.NewInt(7)) != 0 {
+		t.Error("Expected Completed to report the just-saved range")
+	}
+}
+
+func TestFileCheckpointStore_Compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	left := CheckpointEnvelope{A: 0, B: 1, P: big.NewInt(2), Q: big.NewInt(3), T: big.NewInt(5)}
+	right := CheckpointEnvelope{A: 1, B: 2, P: big.NewInt(7), Q: big.NewInt(11), T: big.NewInt(13)}
+	if err := store.Save(left); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Save(right); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	merged, ok := store.Completed(0, 2)
+	if !ok {
+		t.Fatal("Expected sibling ranges to be compacted into a parent range")
+	}
+	wantP, _, _ := CombineResults([]config.Result{
+		{P: left.P, Q: left.Q, T: left.T},
+		{P: right.P, Q: right.Q, T: right.T},
+	})
+	if merged.P.Cmp(wantP) != 0 {
+		t.Errorf("Expected compacted P to match CombineResults, got %s want %s", merged.P, wantP)
+	}
+
+	if _, ok := store.Completed(0, 1); ok {
+		t.Error("Expected the left leaf to be evicted from the index once its parent is compacted")
+	}
+	if _, ok := store.Completed(1, 2); ok {
+		t.Error("Expected the right leaf to be evicted from the index once its parent is compacted")
+	}
+	if len(store.Manifest()) != 1 {
+		t.Errorf("Expected compaction to leave only the merged parent in the index, got %d entries", len(store.Manifest()))
+	}
+}
+
+func TestFileCheckpointStore_CompactionCascadesUpMultipleLevels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	// Four adjacent leaves complete a two-level binary tree: [0,1)+[1,2)
+	// fold into [0,2), [2,3)+[3,4) fold into [2,4), and completing the
+	// last of those four should cascade all the way up to [0,4) in one
+	// Save call, leaving nothing but the grandparent in the index.
+	leaves := []CheckpointEnvelope{
+		{A: 0, B: 1, P: big.NewInt(2), Q: big.NewInt(3), T: big.NewInt(5)},
+		{A: 1, B: 2, P: big.NewInt(7), Q: big.NewInt(11), T: big.NewInt(13)},
+		{A: 2, B: 3, P: big.NewInt(17), Q: big.NewInt(19), T: big.NewInt(23)},
+		{A: 3, B: 4, P: big.NewInt(29), Q: big.NewInt(31), T: big.NewInt(37)},
+	}
+	for _, leaf := range leaves {
+		if err := store.Save(leaf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if _, ok := store.Completed(0, 4); !ok {
+		t.Fatal("Expected completing the last leaf to cascade compaction up to the root range")
+	}
+	if len(store.Manifest()) != 1 {
+		t.Errorf("Expected cascading compaction to leave a single root entry, got %d", len(store.Manifest()))
+	}
+	for _, leaf := range leaves {
+		if _, ok := store.Completed(leaf.A, leaf.B); ok {
+			t.Errorf("Expected leaf [%d,%d) to be evicted once it's folded into the root", leaf.A, leaf.B)
+		}
+	}
+	if _, ok := store.Completed(0, 2); ok {
+		t.Error("Expected the intermediate [0,2) parent to be evicted once it's folded into the root")
+	}
+	if _, ok := store.Completed(2, 4); ok {
+		t.Error("Expected the intermediate [2,4) parent to be evicted once it's folded into the root")
+	}
+}
+
+func TestFileCheckpointStore_CorruptEntryIsDiscarded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Save(CheckpointEnvelope{A: 0, B: 1, P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Unexpected error saving checkpoint: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Unexpected error closing store: %v", err)
+	}
+
+	// #nosec G304 -- path is a t.TempDir() file under test control
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading log: %v", err)
+	}
+	data[len(data)-1] ^= 0xFF
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Unexpected error corrupting log: %v", err)
+	}
+
+	reopened, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error reopening store: %v", err)
+	}
+	defer reopened.Close()
+
+	if _, ok := reopened.Completed(0, 1); ok {
+		t.Error("Expected a corrupted entry to be discarded rather than trusted")
+	}
+	if len(reopened.CorruptRanges()) != 1 {
+		t.Errorf("Expected exactly one corrupt range reported, got %d", len(reopened.CorruptRanges()))
+	}
+}
+
+func TestFileCheckpointStore_Manifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Save(CheckpointEnvelope{A: 0, B: 1, P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Save(CheckpointEnvelope{A: 1, B: 2, P: big.NewInt(2), Q: big.NewInt(2), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	manifest := store.Manifest()
+	if len(manifest) != 1 {
+		t.Fatalf("Expected 1 manifest entry (the compacted parent; both leaves are evicted on compaction), got %d", len(manifest))
+	}
+	for _, entry := range manifest {
+		if entry.Hash == 0 {
+			t.Errorf("Expected a non-zero checksum for range [%d,%d)", entry.A, entry.B)
+		}
+		if entry.File != path {
+			t.Errorf("Expected manifest entry File to be the store path, got %q", entry.File)
+		}
+	}
+}
+
+func TestFileCheckpointStore_Prune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Save(CheckpointEnvelope{A: 0, B: 1, P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Save(CheckpointEnvelope{A: 1, B: 2, P: big.NewInt(2), Q: big.NewInt(2), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(store.Manifest()) != 1 {
+		t.Fatalf("Expected 1 entry before pruning (the compacted parent; leaves are evicted on compaction), got %d", len(store.Manifest()))
+	}
+
+	if err := store.Prune(); err != nil {
+		t.Fatalf("Unexpected error pruning: %v", err)
+	}
+	if len(store.Manifest()) != 1 {
+		t.Errorf("Expected pruning to preserve the index contents (1 entry), got %d", len(store.Manifest()))
+	}
+
+	result, ok := store.Completed(0, 2)
+	if !ok || result.P.Cmp(big.NewInt(2)) != 0 {
+		t.Error("Expected the compacted parent range to survive pruning")
+	}
+
+	if err := store.Save(CheckpointEnvelope{A: 2, B: 3, P: big.NewInt(3), Q: big.NewInt(3), T: big.NewInt(0)}); err != nil {
+		t.Fatalf("Expected store to remain writable after pruning, got error: %v", err)
+	}
+}
+
+func TestComputePQT_WithCheckpointStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	cfg := config.Default()
+	ctx := withCheckpointContext(context.Background(), store)
+
+	P1, Q1, T1, err := ComputePQT(ctx, 0, 10, cfg, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, ok := store.Completed(0, 10); !ok {
+		t.Fatal("Expected range to be checkpointed after computation")
+	}
+
+	// Resuming should short-circuit straight to the persisted result.
+	P2, Q2, T2, err := ComputePQT(ctx, 0, 10, cfg, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if P1.Cmp(P2) != 0 || Q1.Cmp(Q2) != 0 || T1.Cmp(T2) != 0 {
+		t.Error("Expected resumed computation to match the checkpointed result")
+	}
+}
@@ -3,9 +3,9 @@ package calculator
 import (
 	"context"
 	"fmt"
-	"math/big"
 	"runtime"
 
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/arith"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
 )
 
@@ -13,17 +13,61 @@ import (
 // It provides a high-level interface for computing π to arbitrary precision
 // using the fastest known algorithm.
 type Calculator struct {
-	cfg  *config.Config
-	pool PoolInterface
+	cfg        *config.Config
+	pool       PoolInterface
+	checkpoint CheckpointStore
+	progress   ProgressReporter
+	backend    arith.Backend
+}
+
+// Option configures optional Calculator behavior beyond the required
+// config and pool, without breaking existing callers of New.
+type Option func(*Calculator)
+
+// WithCheckpointStore makes ComputePi consult store for already-completed
+// subranges and persist newly-completed ones, so an interrupted run can
+// resume instead of recomputing from scratch.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(c *Calculator) {
+		c.checkpoint = store
+	}
+}
+
+// WithProgressReporter makes ComputePi report TermCompleted, ChunkCompleted,
+// and Phase events to reporter as the computation proceeds, in place of the
+// old package-level SetProgressCallback.
+func WithProgressReporter(reporter ProgressReporter) Option {
+	return func(c *Calculator) {
+		c.progress = reporter
+	}
+}
+
+// WithArithBackend selects the arith.Backend used to assemble the final
+// pi = 426880*sqrt(10005)*Q/T value from the binary-splitting result.
+// Defaults to arith.BigBackend{} (math/big); a cgo-backed GMP/MPFR
+// backend (build tag chudnovsky_gmp) can be passed instead. The P/Q/T
+// binary-splitting recursion itself is unaffected by this choice and
+// dominates runtime at scale, so selecting GMPBackend here is not a
+// meaningful speedup for ComputePi as a whole yet - see the arith
+// package doc comment.
+func WithArithBackend(backend arith.Backend) Option {
+	return func(c *Calculator) {
+		c.backend = backend
+	}
 }
 
 // New creates a new Chudnovsky calculator with the given configuration.
 // The pool parameter can be nil to use sequential computation only.
-func New(cfg *config.Config, pool PoolInterface) *Calculator {
-	return &Calculator{
-		cfg:  cfg,
-		pool: pool,
+func New(cfg *config.Config, pool PoolInterface, opts ...Option) *Calculator {
+	c := &Calculator{
+		cfg:     cfg,
+		pool:    pool,
+		backend: arith.BigBackend{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // ComputePi computes π to the specified number of digits.
@@ -49,27 +93,34 @@ func (c *Calculator) ComputePi(ctx context.Context, digits int64) (string, error
 	prec := uint(float64(digits) * c.cfg.BitsPerDigit)
 	terms := digits/c.cfg.DigitsPerTerm + 1
 
+	if c.checkpoint != nil {
+		ctx = withCheckpointContext(ctx, c.checkpoint)
+	}
+	if c.progress != nil {
+		ctx = AttachProgressReporter(ctx, c.progress)
+	}
+
+	reportPhase(ctx, "pqt")
 	// Compute P, Q, T
 	_, Q, T, err := ComputePQT(ctx, 0, terms, c.cfg, c.pool)
 	if err != nil {
 		return "", fmt.Errorf("failed to compute PQT: %w", err)
 	}
+	reportPhase(ctx, "finalize")
 
 	// Final Calculation: pi = (426880 * sqrt(10005) * Q) / T
-	bigQ := new(big.Float).SetInt(Q)
-	bigT := new(big.Float).SetInt(T)
-
-	valE := new(big.Float).SetPrec(prec).SetInt64(10005)
-	sqrtE := new(big.Float).SetPrec(prec).Sqrt(valE)
+	b := c.backend
+	fQ := b.FloatFromInt(b.IntFromBig(Q), prec)
+	fT := b.FloatFromInt(b.IntFromBig(T), prec)
 
-	multi := new(big.Float).SetPrec(prec).SetInt64(426880)
-	num := new(big.Float).SetPrec(prec).Mul(multi, sqrtE)
-	num.Mul(num, bigQ)
+	sqrtE := b.Sqrt(b.NewFloat(10005, prec))
+	num := b.Mul(b.NewFloat(426880, prec), sqrtE)
+	num = b.Mul(num, fQ)
 
-	pi := new(big.Float).SetPrec(prec).Quo(num, bigT)
+	pi := b.Quo(num, fT)
 
 	// Format pi as string
-	piStr := fmt.Sprintf("%.*f", int(digits), pi)
+	piStr := b.Text(pi, int(digits))
 	return piStr, nil
 }
 
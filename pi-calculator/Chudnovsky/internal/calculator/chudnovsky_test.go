@@ -76,6 +76,26 @@ func TestCalculator_ComputePi_ContextCancellation(t *testing.T) {
 	}
 }
 
+func TestCalculator_ComputePi_WithProgressReporter(t *testing.T) {
+	cfg := config.Default()
+	var phases []string
+	reporter := NewFuncReporter(func(e Event) {
+		if phase, ok := e.(Phase); ok {
+			phases = append(phases, phase.Name)
+		}
+	})
+	calc := New(cfg, nil, WithProgressReporter(reporter))
+	ctx := context.Background()
+
+	if _, err := calc.ComputePi(ctx, 10); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(phases) != 2 || phases[0] != "pqt" || phases[1] != "finalize" {
+		t.Errorf("Expected phases [pqt finalize], got %v", phases)
+	}
+}
+
 func TestGetNumCPU(t *testing.T) {
 	numCPU := GetNumCPU()
 	if numCPU < 1 {
@@ -0,0 +1,103 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// MachinCalculator computes π via Machin's formula,
+// pi = 16*atan(1/5) - 4*atan(1/239), each arctan evaluated with its
+// Taylor series. It converges linearly (roughly 1.4 decimal digits per
+// term of the atan(1/5) series) rather than Chudnovsky's ~14 digits per
+// term, so it exists as a simple, independently-derived cross-check
+// rather than a fast path.
+type MachinCalculator struct {
+	cfg *config.Config
+}
+
+// NewMachinCalculator creates a Machin's-formula π calculator.
+func NewMachinCalculator(cfg *config.Config) *MachinCalculator {
+	return &MachinCalculator{cfg: cfg}
+}
+
+// Name implements PiAlgorithm.
+func (c *MachinCalculator) Name() string { return "machin" }
+
+// seriesCancelCheckInterval controls how often atanReciprocalSeries checks
+// ctx for cancellation, mirroring verifier's cancelCheckInterval so the
+// check stays cheap relative to the per-term big.Float work.
+const seriesCancelCheckInterval = 4096
+
+// ComputePi implements PiAlgorithm using pi = 16*atan(1/5) - 4*atan(1/239).
+func (c *MachinCalculator) ComputePi(ctx context.Context, digits int64) (string, error) {
+	if digits < 1 {
+		return "", fmt.Errorf("digits must be at least 1, got %d", digits)
+	}
+	if digits > c.cfg.MaxDigits {
+		return "", fmt.Errorf("digits exceeds maximum allowed (%d), got %d", c.cfg.MaxDigits, digits)
+	}
+
+	prec := bitsForDigits(digits)
+
+	atan5, err := atanReciprocalSeries(ctx, 5, prec)
+	if err != nil {
+		return "", err
+	}
+	atan239, err := atanReciprocalSeries(ctx, 239, prec)
+	if err != nil {
+		return "", err
+	}
+
+	sixteen := new(big.Float).SetPrec(prec).SetInt64(16)
+	four := new(big.Float).SetPrec(prec).SetInt64(4)
+
+	pi := new(big.Float).SetPrec(prec).Mul(sixteen, atan5)
+	pi.Sub(pi, new(big.Float).SetPrec(prec).Mul(four, atan239))
+
+	return pi.Text('f', int(digits)), nil
+}
+
+// atanReciprocalSeries computes atan(1/x) via its Taylor series
+// sum_{k=0}^inf (-1)^k / ((2k+1) * x^(2k+1)), stopping once a term's
+// binary exponent falls below -prec (i.e. it can no longer affect the
+// result at the working precision).
+func atanReciprocalSeries(ctx context.Context, x int64, prec uint) (*big.Float, error) {
+	invX := new(big.Float).SetPrec(prec).Quo(
+		new(big.Float).SetPrec(prec).SetInt64(1),
+		new(big.Float).SetPrec(prec).SetInt64(x),
+	)
+	invX2 := new(big.Float).SetPrec(prec).Mul(invX, invX)
+
+	sum := new(big.Float).SetPrec(prec)
+	term := new(big.Float).SetPrec(prec).Set(invX)
+	negative := false
+
+	for k := int64(0); ; k++ {
+		if k%seriesCancelCheckInterval == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+
+		denom := new(big.Float).SetPrec(prec).SetInt64(2*k + 1)
+		contribution := new(big.Float).SetPrec(prec).Quo(term, denom)
+		if negative {
+			sum.Sub(sum, contribution)
+		} else {
+			sum.Add(sum, contribution)
+		}
+		negative = !negative
+
+		term.Mul(term, invX2)
+		if exp := term.MantExp(nil); exp < -int(prec) {
+			break
+		}
+	}
+
+	return sum, nil
+}
@@ -0,0 +1,52 @@
+package calculator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+func TestMachinCalculator_ComputePi(t *testing.T) {
+	cfg := config.Default()
+	calc := NewMachinCalculator(cfg)
+
+	piStr, err := calc.ComputePi(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(piStr, knownPiPrefix) {
+		t.Errorf("Expected prefix %q, got %q", knownPiPrefix, piStr)
+	}
+}
+
+func TestMachinCalculator_Name(t *testing.T) {
+	calc := NewMachinCalculator(config.Default())
+	if calc.Name() != "machin" {
+		t.Errorf("Expected name 'machin', got %q", calc.Name())
+	}
+}
+
+func TestMachinCalculator_InvalidInput(t *testing.T) {
+	cfg := config.Default()
+	calc := NewMachinCalculator(cfg)
+	ctx := context.Background()
+
+	if _, err := calc.ComputePi(ctx, -5); err == nil {
+		t.Error("Expected error for negative digits")
+	}
+	if _, err := calc.ComputePi(ctx, cfg.MaxDigits+1); err == nil {
+		t.Error("Expected error for exceeding max digits")
+	}
+}
+
+func TestMachinCalculator_ContextCancellation(t *testing.T) {
+	calc := NewMachinCalculator(config.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := calc.ComputePi(ctx, 1000); err == nil {
+		t.Error("Expected error due to context cancellation")
+	}
+}
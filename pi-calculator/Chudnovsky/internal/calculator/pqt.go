@@ -7,27 +7,12 @@ import (
 	"context"
 	"math/big"
 	"runtime"
-	"sync/atomic"
+	"time"
 
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/metrics"
 )
 
-// ProgressCallback is a function type for reporting computation progress.
-// It is called with the current number of terms computed.
-type ProgressCallback func(current int64)
-
-var (
-	// progressCallback is set by the calculator to report progress
-	progressCallback ProgressCallback
-	progressCounter  int64
-)
-
-// SetProgressCallback sets the callback function for progress updates.
-// The callback will be invoked each time a term is computed.
-func SetProgressCallback(callback ProgressCallback) {
-	progressCallback = callback
-}
-
 // Result represents a PQT computation result from the Chudnovsky algorithm.
 type Result = config.Result
 
@@ -48,6 +33,7 @@ func CombineResults(results []Result) (P, Q, T *big.Int) {
 			new(big.Int).Mul(results[1].Q, results[0].T),
 			new(big.Int).Mul(results[0].P, results[1].T),
 		)
+		metrics.ObserveBigIntBitLengths(P, Q, T)
 		return P, Q, T
 	}
 
@@ -62,6 +48,7 @@ func CombineResults(results []Result) (P, Q, T *big.Int) {
 		new(big.Int).Mul(q2, t1),
 		new(big.Int).Mul(p1, t2),
 	)
+	metrics.ObserveBigIntBitLengths(P, Q, T)
 	return P, Q, T
 }
 
@@ -84,36 +71,7 @@ func ComputePQTSequential(a, b int64) (P, Q, T *big.Int) {
 	}
 
 	if b-a == 1 {
-		// Update progress for base case
-		if progressCallback != nil {
-			atomic.AddInt64(&progressCounter, 1)
-			progressCallback(atomic.LoadInt64(&progressCounter))
-		}
-		P = big.NewInt(1)
-		Q = big.NewInt(1)
-		if a > 0 {
-			// P = (6a-5)(2a-1)(6a-1)
-			p1 := big.NewInt(6*a - 5)
-			p2 := big.NewInt(2*a - 1)
-			p3 := big.NewInt(6*a - 1)
-			P.Mul(p1, p2).Mul(P, p3)
-
-			// Q = a^3 * C^3 / 24
-			a3 := new(big.Int).Mul(big.NewInt(a*a), big.NewInt(a))
-			Q.Mul(a3, C3).Div(Q, big.NewInt(24))
-		}
-		// T = P * (A + Ba)
-		term := new(big.Int).Mul(B, big.NewInt(a))
-		term.Add(term, A)
-		T = new(big.Int).Mul(P, term)
-		if a%2 == 1 {
-			T.Neg(T)
-		}
-		// Yield frequently to allow scheduler to switch
-		if a%50 == 0 {
-			runtime.Gosched()
-		}
-		return P, Q, T
+		return leafPQT(a)
 	}
 
 	// For sequential computation, split recursively
@@ -126,6 +84,134 @@ func ComputePQTSequential(a, b int64) (P, Q, T *big.Int) {
 	return P, Q, T
 }
 
+// CombineResultsCtx is the cancellable counterpart to CombineResults. It
+// checks ctx before each pairwise merge so combining a large number of
+// checkpointed or worker-pool results can be abandoned promptly once the
+// caller's context is done.
+//
+//nolint:gocritic // P, Q, T are exported return values, capitalization is intentional
+func CombineResultsCtx(ctx context.Context, results []Result) (P, Q, T *big.Int, err error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	default:
+	}
+
+	if len(results) == 1 {
+		return results[0].P, results[0].Q, results[0].T, nil
+	}
+	if len(results) == 2 {
+		P = new(big.Int).Mul(results[0].P, results[1].P)
+		Q = new(big.Int).Mul(results[0].Q, results[1].Q)
+		T = new(big.Int).Add(
+			new(big.Int).Mul(results[1].Q, results[0].T),
+			new(big.Int).Mul(results[0].P, results[1].T),
+		)
+		metrics.ObserveBigIntBitLengths(P, Q, T)
+		return P, Q, T, nil
+	}
+
+	mid := len(results) / 2
+	p1, q1, t1, err := CombineResultsCtx(ctx, results[:mid])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p2, q2, t2, err := CombineResultsCtx(ctx, results[mid:])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	default:
+	}
+	P = new(big.Int).Mul(p1, p2)
+	Q = new(big.Int).Mul(q1, q2)
+	T = new(big.Int).Add(
+		new(big.Int).Mul(q2, t1),
+		new(big.Int).Mul(p1, t2),
+	)
+	metrics.ObserveBigIntBitLengths(P, Q, T)
+	return P, Q, T, nil
+}
+
+// ComputePQTSequentialCtx is the cancellable counterpart to
+// ComputePQTSequential. It checks ctx at every recursive split and returns
+// immediately with (nil, nil, nil, ctx.Err()) once cancelled, instead of
+// burning CPU to the end of an already-abandoned computation.
+//
+//nolint:gocritic // P, Q, T are exported return values, capitalization is intentional
+func ComputePQTSequentialCtx(ctx context.Context, a, b int64) (P, Q, T *big.Int, err error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	default:
+	}
+
+	if a >= b {
+		return big.NewInt(1), big.NewInt(1), big.NewInt(0), nil
+	}
+
+	if b-a == 1 {
+		P, Q, T = leafPQT(a)
+		reportTerm(ctx, a)
+		return P, Q, T, nil
+	}
+
+	mid := (a + b) / 2
+	p1, q1, t1, err := ComputePQTSequentialCtx(ctx, a, mid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	p2, q2, t2, err := ComputePQTSequentialCtx(ctx, mid, b)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	default:
+	}
+	P = new(big.Int).Mul(p1, p2)
+	Q = new(big.Int).Mul(q1, q2)
+	T = new(big.Int).Add(new(big.Int).Mul(q2, t1), new(big.Int).Mul(p1, t2))
+	return P, Q, T, nil
+}
+
+// leafPQT computes the P, Q, T contribution of the single base-case term a,
+// shared by both ComputePQTSequential and ComputePQTSequentialCtx.
+//
+//nolint:gocritic // P, Q, T are exported return values, capitalization is intentional
+func leafPQT(a int64) (P, Q, T *big.Int) {
+	P = big.NewInt(1)
+	Q = big.NewInt(1)
+	if a > 0 {
+		// P = (6a-5)(2a-1)(6a-1)
+		p1 := big.NewInt(6*a - 5)
+		p2 := big.NewInt(2*a - 1)
+		p3 := big.NewInt(6*a - 1)
+		P.Mul(p1, p2).Mul(P, p3)
+
+		// Q = a^3 * C^3 / 24
+		a3 := new(big.Int).Mul(big.NewInt(a*a), big.NewInt(a))
+		Q.Mul(a3, C3).Div(Q, big.NewInt(24))
+	}
+	// T = P * (A + Ba)
+	term := new(big.Int).Mul(B, big.NewInt(a))
+	term.Add(term, A)
+	T = new(big.Int).Mul(P, term)
+	if a%2 == 1 {
+		T.Neg(T)
+	}
+	// Yield frequently to allow scheduler to switch
+	if a%50 == 0 {
+		runtime.Gosched()
+	}
+	return P, Q, T
+}
+
 // ComputePQT computes P, Q, T values using parallel computation when beneficial.
 // It automatically chooses between sequential and parallel computation based on
 // the range size and available worker pool.
@@ -152,19 +238,61 @@ func ComputePQT(ctx context.Context, a, b int64, cfg *config.Config, pool PoolIn
 	default:
 	}
 
+	store := checkpointStoreFromContext(ctx)
+	if store != nil {
+		if result, ok := store.Completed(a, b); ok {
+			reportCheckpointSkip(ctx, a, b)
+			return result.P, result.Q, result.T, nil
+		}
+	}
+
 	// Use worker pool for larger ranges, but ensure chunks are small enough
 	if rangeSize > cfg.MinRangeForWorkerPool && pool != nil {
-		return computePQTParallel(ctx, a, b, rangeSize, cfg, pool)
+		if stealer, ok := pool.(RecursiveSubmitter); ok {
+			P, Q, T, err = computePQTWorkStealing(ctx, a, b, cfg, stealer)
+		} else {
+			P, Q, T, err = computePQTParallel(ctx, a, b, rangeSize, cfg, pool)
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		metrics.ObserveBigIntBitLengths(P, Q, T)
+		return P, Q, T, nil
 	}
 
 	// For small ranges or if no worker pool, use sequential
-	P, Q, T = ComputePQTSequential(a, b)
+	P, Q, T, err = ComputePQTSequentialCtx(ctx, a, b)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	saveCheckpoint(store, a, b, P, Q, T)
+	metrics.ObserveBigIntBitLengths(P, Q, T)
 	return P, Q, T, nil
 }
 
+// saveCheckpoint persists a completed [a,b) subrange if a CheckpointStore is
+// in play. Failures are non-fatal: losing a checkpoint only costs recompute
+// time on resume, so it must never fail the in-progress computation.
+func saveCheckpoint(store CheckpointStore, a, b int64, P, Q, T *big.Int) {
+	if store == nil {
+		return
+	}
+	_ = store.Save(CheckpointEnvelope{A: a, B: b, P: P, Q: Q, T: T})
+}
+
+// reportCheckpointSkip reports a [a,b) subrange that was already complete
+// in the CheckpointStore as a ChunkCompleted event (Elapsed 0, since no
+// work actually ran), so a resumed run's progress reporting starts from
+// where the previous run left off instead of sitting near zero until new
+// leaf work catches back up past the terms a checkpoint hit skipped
+// entirely.
+func reportCheckpointSkip(ctx context.Context, a, b int64) {
+	reportChunk(ctx, a, b, 0)
+}
+
 // PoolInterface defines the interface for worker pools
 type PoolInterface interface {
-	Submit(start, end int64, computeFn func(a, b int64) Result) <-chan Result
+	Submit(start, end int64, computeFn func(ctx context.Context, a, b int64) (Result, error)) <-chan Result
 }
 
 // computePQTParallel computes PQT using parallel worker pool
@@ -184,8 +312,7 @@ func computePQTParallel(ctx context.Context, a, b, rangeSize int64, cfg *config.
 
 	// Ensure we have at least one valid chunk
 	if numChunks < 1 {
-		P, Q, T = ComputePQTSequential(a, b)
-		return P, Q, T, nil
+		return ComputePQTSequentialCtx(ctx, a, b)
 	}
 
 	chunkSize := rangeSize / int64(numChunks)
@@ -195,6 +322,10 @@ func computePQTParallel(ctx context.Context, a, b, rangeSize int64, cfg *config.
 		numChunks = int(rangeSize)
 	}
 
+	store := checkpointStoreFromContext(ctx)
+	// The pool runs computeFn under its own context, which carries no
+	// progress reporter, so chunk events are reported against this one.
+	progressCtx := ctx
 	results := make([]Result, numChunks)
 	resultChans := make([]<-chan Result, numChunks)
 
@@ -212,16 +343,32 @@ func computePQTParallel(ctx context.Context, a, b, rangeSize int64, cfg *config.
 				end = b
 			}
 		}
+		if store != nil {
+			if result, ok := store.Completed(start, end); ok {
+				reportCheckpointSkip(ctx, start, end)
+				doneChan := make(chan Result, 1)
+				doneChan <- result
+				close(doneChan)
+				resultChans[i] = doneChan
+				continue
+			}
+		}
 		if start < b {
 			startCopy, endCopy := start, end
-			resultChans[i] = pool.Submit(startCopy, endCopy, func(a, b int64) Result {
-				p, q, t := ComputePQTSequential(a, b)
-				return Result{P: p, Q: q, T: t}
+			resultChans[i] = pool.Submit(startCopy, endCopy, func(ctx context.Context, a, b int64) (Result, error) {
+				started := time.Now()
+				p, q, t, err := ComputePQTSequentialCtx(ctx, a, b)
+				if err != nil {
+					return Result{}, err
+				}
+				saveCheckpoint(store, a, b, p, q, t)
+				reportChunk(progressCtx, a, b, time.Since(started))
+				return Result{P: p, Q: q, T: t, Range: [2]int64{a, b}}, nil
 			})
 		} else {
 			// Empty chunk - return identity
 			identityChan := make(chan Result, 1)
-			identityChan <- Result{P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0)}
+			identityChan <- Result{P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0), Range: [2]int64{start, end}}
 			close(identityChan)
 			resultChans[i] = identityChan
 		}
@@ -234,8 +381,93 @@ func computePQTParallel(ctx context.Context, a, b, rangeSize int64, cfg *config.
 			return nil, nil, nil, ctx.Err()
 		case results[i] = <-resultChans[i]:
 		}
+		if results[i].Err != nil {
+			return nil, nil, nil, results[i].Err
+		}
+	}
+
+	if cfg.SpillThresholdBytes > 0 {
+		return CombineResultsSpillingCtx(ctx, results, cfg.SpillThresholdBytes)
+	}
+	return CombineResultsCtx(ctx, results)
+}
+
+// RecursiveSubmitter is implemented by pools that support ComputePQT's
+// recursive work-stealing split (workerpool.StealPool), as opposed to
+// PoolInterface.Submit's flatten-into-independent-chunks model.
+// SubmitRecursive pushes one half of a split onto the calling worker's own
+// deque (identified via ctx) rather than a shared queue, so the other half
+// can be computed locally without round-tripping through it.
+type RecursiveSubmitter interface {
+	PoolInterface
+
+	// SubmitRecursive submits [start,end) for execution and returns a
+	// channel to receive the result, same as Submit, but additionally
+	// uses ctx to recognize when it's being called from inside a task the
+	// pool is already running so the new task lands on that same worker.
+	SubmitRecursive(ctx context.Context, start, end int64, computeFn func(ctx context.Context, a, b int64) (Result, error)) <-chan Result
+}
+
+// computePQTWorkStealing computes PQT by recursively splitting [a,b) at
+// its midpoint: it submits the right half onto the current worker's own
+// deque (to be stolen by an idle worker, or run locally if none steals it
+// in time) and computes the left half inline, combining both on return.
+// Unlike computePQTParallel's fixed chunk count, this adapts to however
+// deep a given subtree's recursion goes, since any worker that finishes
+// early can steal work from whichever sibling subtree is still running.
+//
+//nolint:gocritic // P, Q, T are return values, capitalization is intentional
+func computePQTWorkStealing(ctx context.Context, a, b int64, cfg *config.Config, pool RecursiveSubmitter) (P, Q, T *big.Int, err error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	default:
+	}
+
+	store := checkpointStoreFromContext(ctx)
+	if store != nil {
+		if result, ok := store.Completed(a, b); ok {
+			reportCheckpointSkip(ctx, a, b)
+			return result.P, result.Q, result.T, nil
+		}
+	}
+
+	rangeSize := b - a
+	if rangeSize <= cfg.StealThreshold {
+		started := time.Now()
+		P, Q, T, err = ComputePQTSequentialCtx(ctx, a, b)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		saveCheckpoint(store, a, b, P, Q, T)
+		reportChunk(ctx, a, b, time.Since(started))
+		return P, Q, T, nil
+	}
+
+	mid := a + rangeSize/2
+	rightChan := pool.SubmitRecursive(ctx, mid, b, func(ctx context.Context, start, end int64) (Result, error) {
+		p, q, t, err := computePQTWorkStealing(ctx, start, end, cfg, pool)
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{P: p, Q: q, T: t, Range: [2]int64{start, end}}, nil
+	})
+
+	leftP, leftQ, leftT, err := computePQTWorkStealing(ctx, a, mid, cfg, pool)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var right Result
+	select {
+	case <-ctx.Done():
+		return nil, nil, nil, ctx.Err()
+	case right = <-rightChan:
+	}
+	if right.Err != nil {
+		return nil, nil, nil, right.Err
 	}
 
-	P, Q, T = CombineResults(results)
+	P, Q, T = CombineResults([]Result{{P: leftP, Q: leftQ, T: leftT}, right})
 	return P, Q, T, nil
 }
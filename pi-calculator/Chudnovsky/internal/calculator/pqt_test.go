@@ -3,7 +3,10 @@ package calculator
 import (
 	"context"
 	"math/big"
+	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/workerpool"
@@ -101,6 +104,39 @@ func TestComputePQTParallel_LargeRange(t *testing.T) {
 	}
 }
 
+func TestComputePQT_WithStealPool(t *testing.T) {
+	cfg := config.Default()
+	pool := workerpool.NewStealPool(4)
+	defer pool.Close()
+	ctx := context.Background()
+
+	P, Q, T, err := ComputePQT(ctx, 0, 2000, cfg, pool)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	wantP, wantQ, wantT := ComputePQTSequential(0, 2000)
+	if P.Cmp(wantP) != 0 || Q.Cmp(wantQ) != 0 || T.Cmp(wantT) != 0 {
+		t.Error("Expected work-stealing result to match sequential computation")
+	}
+}
+
+func TestComputePQTWorkStealing_ContextCancellation(t *testing.T) {
+	cfg := config.Default()
+	pool := workerpool.NewStealPool(2)
+	defer pool.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	P, Q, T, err := ComputePQT(ctx, 0, 2000, cfg, pool)
+	if err == nil {
+		t.Error("Expected error due to context cancellation")
+	}
+	if P != nil || Q != nil || T != nil {
+		t.Error("Expected nil results when context is cancelled")
+	}
+}
+
 func TestComputePQTParallel_ContextCancellation(t *testing.T) {
 	cfg := config.Default()
 	pool := workerpool.New(2)
@@ -117,18 +153,112 @@ func TestComputePQTParallel_ContextCancellation(t *testing.T) {
 	}
 }
 
-func TestSetProgressCallback(t *testing.T) {
-	var called bool
-	callback := func(current int64) {
-		called = true
+func TestComputePQT_CancelLargeJobUnwindsGoroutines(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping goroutine-unwind test in short mode")
 	}
 
-	SetProgressCallback(callback)
-	// Trigger callback by computing
-	ComputePQTSequential(0, 1)
+	cfg := config.Default()
+	// 10M digits worth of terms, spread across a small pool so cancellation
+	// genuinely has to interrupt work in flight rather than finishing first.
+	terms := int64(10_000_000)/cfg.DigitsPerTerm + 1
+
+	baseline := runtime.NumGoroutine()
+
+	pool := workerpool.New(4)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, _, _, err := ComputePQT(ctx, 0, terms, cfg, pool)
+	if err == nil {
+		t.Fatal("Expected context cancellation error for a job that can't finish in 100ms")
+	}
+	pool.Close()
 
-	// Note: callback may or may not be called depending on implementation
-	_ = called
+	deadline := time.Now().Add(1 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= baseline+2 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("Goroutine count did not return to baseline: started at %d, still at %d", baseline, runtime.NumGoroutine())
+}
+
+func TestComputePQT_ResumeReportsSkippedProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ckpt.gob")
+	store, err := NewFileCheckpointStore(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	cfg := config.Default()
+	ctx := withCheckpointContext(context.Background(), store)
+
+	if _, _, _, err := ComputePQT(ctx, 0, 10, cfg, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var reported int64
+	reporter := NewFuncReporter(func(e Event) {
+		if chunk, ok := e.(ChunkCompleted); ok {
+			reported = chunk.End - chunk.Start
+		}
+	})
+	ctx = AttachProgressReporter(ctx, reporter)
+
+	// Resuming should short-circuit to the checkpointed result, but still
+	// report a ChunkCompleted for the skipped range so a resumed run's
+	// progress reporting starts from where the previous run left off
+	// instead of sitting near zero.
+	if _, _, _, err := ComputePQT(ctx, 0, 10, cfg, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reported < 10 {
+		t.Errorf("Expected a ChunkCompleted covering at least the skipped range width (10), got %d", reported)
+	}
+}
+
+func TestFuncReporter_ReportsEachEvent(t *testing.T) {
+	var events []Event
+	reporter := NewFuncReporter(func(e Event) {
+		events = append(events, e)
+	})
+	ctx := AttachProgressReporter(context.Background(), reporter)
+
+	if _, _, _, err := ComputePQTSequentialCtx(ctx, 0, 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 TermCompleted events for a 3-term range, got %d", len(events))
+	}
+	for _, e := range events {
+		if _, ok := e.(TermCompleted); !ok {
+			t.Errorf("Expected a TermCompleted event, got %T", e)
+		}
+	}
+}
+
+func TestChannelReporter_DropsWhenFull(t *testing.T) {
+	reporter := NewChannelReporter(1)
+	ctx := AttachProgressReporter(context.Background(), reporter)
+
+	if _, _, _, err := ComputePQTSequentialCtx(ctx, 0, 5); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A buffer of 1 against 5 TermCompleted events must drop some rather
+	// than block the computation.
+	if reporter.Dropped() == 0 {
+		t.Error("Expected some events to be dropped once the buffer filled")
+	}
+	select {
+	case <-reporter.Events():
+	default:
+		t.Error("Expected at least one event to have made it onto the channel")
+	}
 }
 
 func TestComputePQTSequential_EdgeCases(t *testing.T) {
@@ -0,0 +1,163 @@
+package calculator
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/metrics"
+)
+
+// Event is implemented by every progress event a computation can emit.
+// Consumers type-switch on the concrete type to decide what to do with it.
+type Event interface {
+	isProgressEvent()
+}
+
+// TermCompleted reports that the single base-case term at Index has been
+// computed, the finest-grained signal ComputePQTSequentialCtx emits.
+type TermCompleted struct {
+	Index int64
+}
+
+// ChunkCompleted reports that the contiguous [Start,End) subrange finished
+// in Elapsed wall-clock time. computePQTParallel and computePQTWorkStealing
+// emit one of these per chunk alongside whatever TermCompleted events its
+// individual terms produced, so a consumer that only cares about
+// chunk-level granularity doesn't have to tally TermCompleted itself; it's
+// also emitted once (with Elapsed 0) when a subrange is skipped because a
+// CheckpointStore already had it.
+type ChunkCompleted struct {
+	Start, End int64
+	Elapsed    time.Duration
+}
+
+// Phase marks the start of a named stage of the overall computation, e.g.
+// "pqt" for the binary-splitting recursion and "finalize" for the final
+// sqrt/division step in Calculator.ComputePi, so a consumer can relabel its
+// progress bar instead of inferring the stage from term indices alone.
+type Phase struct {
+	Name string
+}
+
+func (TermCompleted) isProgressEvent()  {}
+func (ChunkCompleted) isProgressEvent() {}
+func (Phase) isProgressEvent()          {}
+
+// ProgressReporter receives Events from a running computation. Report must
+// never block: a slow or stuck consumer must not stall the worker that
+// produced the event, so implementations are expected to drop events they
+// can't keep up with rather than apply backpressure.
+type ProgressReporter interface {
+	Report(Event)
+}
+
+// ChannelReporter is a ProgressReporter that forwards events onto a
+// buffered channel. Once the buffer is full, Report drops the event
+// instead of blocking the caller; Dropped reports how many were lost so a
+// consumer can tell its progress display is falling behind rather than
+// silently under-reporting.
+type ChannelReporter struct {
+	events  chan Event
+	dropped int64
+}
+
+// NewChannelReporter returns a ChannelReporter whose channel holds up to buf
+// pending events before Report starts dropping them.
+func NewChannelReporter(buf int) *ChannelReporter {
+	return &ChannelReporter{events: make(chan Event, buf)}
+}
+
+// Events returns the channel new events are published on. The channel is
+// never closed by ChannelReporter itself; a caller done consuming should
+// simply stop reading from it once the computation it was attached to
+// returns.
+func (r *ChannelReporter) Events() <-chan Event {
+	return r.events
+}
+
+// Dropped reports how many events have been discarded because Events
+// wasn't drained quickly enough.
+func (r *ChannelReporter) Dropped() int64 {
+	return atomic.LoadInt64(&r.dropped)
+}
+
+// Report implements ProgressReporter.
+func (r *ChannelReporter) Report(e Event) {
+	select {
+	case r.events <- e:
+	default:
+		atomic.AddInt64(&r.dropped, 1)
+		metrics.RecordDroppedProgressEvent()
+	}
+}
+
+// FuncReporter is a ProgressReporter that invokes a plain callback for each
+// event, synchronously, on whichever goroutine produced it. It exists for
+// callers that want SetProgressCallback's old simplicity without the
+// package-level global state it used to require.
+type FuncReporter struct {
+	cb func(Event)
+}
+
+// NewFuncReporter returns a ProgressReporter that calls cb for every event.
+// Like ChannelReporter, cb must not block: it runs on the producing
+// worker's goroutine, so a slow cb stalls that worker.
+func NewFuncReporter(cb func(Event)) *FuncReporter {
+	return &FuncReporter{cb: cb}
+}
+
+// Report implements ProgressReporter.
+func (r *FuncReporter) Report(e Event) {
+	if r.cb != nil {
+		r.cb(e)
+	}
+}
+
+// progressContextKey is an unexported type so values stored via
+// AttachProgressReporter cannot collide with keys from other packages,
+// mirroring checkpointContextKey.
+type progressContextKey struct{}
+
+// AttachProgressReporter returns a copy of ctx carrying reporter, so
+// ComputePQT (and anything it calls) can report progress to it without
+// widening their signatures. Unlike CheckpointStore, which is normally
+// attached via the Calculator.WithCheckpointStore Option, this is exported
+// directly because callers that drive ComputePQTSequentialCtx without going
+// through Calculator (pkg/rpc's WorkerService, for instance) need to attach
+// a reporter of their own.
+func AttachProgressReporter(ctx context.Context, reporter ProgressReporter) context.Context {
+	if reporter == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressContextKey{}, reporter)
+}
+
+// progressReporterFromContext retrieves the reporter installed by
+// AttachProgressReporter, if any.
+func progressReporterFromContext(ctx context.Context) ProgressReporter {
+	reporter, _ := ctx.Value(progressContextKey{}).(ProgressReporter)
+	return reporter
+}
+
+// reportTerm reports a single completed term to ctx's reporter, if any.
+func reportTerm(ctx context.Context, index int64) {
+	if reporter := progressReporterFromContext(ctx); reporter != nil {
+		reporter.Report(TermCompleted{Index: index})
+	}
+}
+
+// reportChunk reports a completed (or checkpoint-skipped) [start,end)
+// subrange to ctx's reporter, if any.
+func reportChunk(ctx context.Context, start, end int64, elapsed time.Duration) {
+	if reporter := progressReporterFromContext(ctx); reporter != nil {
+		reporter.Report(ChunkCompleted{Start: start, End: end, Elapsed: elapsed})
+	}
+}
+
+// reportPhase reports the start of a named phase to ctx's reporter, if any.
+func reportPhase(ctx context.Context, name string) {
+	if reporter := progressReporterFromContext(ctx); reporter != nil {
+		reporter.Report(Phase{Name: name})
+	}
+}
@@ -0,0 +1,92 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// RamanujanCalculator computes π via Ramanujan's 1914 series:
+//
+//	1/pi = (2*sqrt(2)/9801) * sum_{k=0}^inf [(4k)!*(1103+26390k)] / [(k!)^4 * 396^(4k)]
+//
+// Each additional term contributes roughly 8 more correct decimal digits.
+// Like AGMCalculator, it exists as an algorithmically independent
+// cross-check against Chudnovsky rather than a faster alternative.
+type RamanujanCalculator struct {
+	cfg *config.Config
+}
+
+// NewRamanujanCalculator creates a Ramanujan-series π calculator.
+func NewRamanujanCalculator(cfg *config.Config) *RamanujanCalculator {
+	return &RamanujanCalculator{cfg: cfg}
+}
+
+// Name implements PiAlgorithm.
+func (c *RamanujanCalculator) Name() string { return "ramanujan" }
+
+// ramanujanDigitsPerTerm is the approximate number of correct decimal
+// digits each additional series term contributes.
+const ramanujanDigitsPerTerm = 8
+
+// ComputePi implements PiAlgorithm using Ramanujan's 1914 series.
+func (c *RamanujanCalculator) ComputePi(ctx context.Context, digits int64) (string, error) {
+	if digits < 1 {
+		return "", fmt.Errorf("digits must be at least 1, got %d", digits)
+	}
+	if digits > c.cfg.MaxDigits {
+		return "", fmt.Errorf("digits exceeds maximum allowed (%d), got %d", c.cfg.MaxDigits, digits)
+	}
+
+	prec := bitsForDigits(digits)
+	terms := digits/ramanujanDigitsPerTerm + 2
+
+	// fact4k = (4k)!, factK4 = (k!)^4, pow396 = 396^(4k); all three are
+	// updated incrementally rather than recomputed from scratch each k.
+	fact4k := big.NewInt(1)
+	factK4 := big.NewInt(1)
+	pow396 := big.NewInt(1)
+	pow396Step := new(big.Int).Exp(big.NewInt(396), big.NewInt(4), nil)
+
+	sum := new(big.Float).SetPrec(prec)
+
+	for k := int64(0); k < terms; k++ {
+		if k%seriesCancelCheckInterval == 0 {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			default:
+			}
+		}
+
+		numerator := new(big.Int).Mul(fact4k, big.NewInt(1103+26390*k))
+		denominator := new(big.Int).Mul(factK4, pow396)
+
+		term := new(big.Float).SetPrec(prec).Quo(
+			new(big.Float).SetPrec(prec).SetInt(numerator),
+			new(big.Float).SetPrec(prec).SetInt(denominator),
+		)
+		sum.Add(sum, term)
+
+		// Advance fact4k, factK4, and pow396 from k to k+1.
+		for j := int64(1); j <= 4; j++ {
+			fact4k.Mul(fact4k, big.NewInt(4*k+j))
+		}
+		kPlus1 := big.NewInt(k + 1)
+		factK4.Mul(factK4, new(big.Int).Exp(kPlus1, big.NewInt(4), nil))
+		pow396.Mul(pow396, pow396Step)
+	}
+
+	two := new(big.Float).SetPrec(prec).SetInt64(2)
+	sqrt2 := new(big.Float).SetPrec(prec).Sqrt(two)
+	nineThousandEightOhOne := new(big.Float).SetPrec(prec).SetInt64(9801)
+
+	// pi = 9801 / (2*sqrt(2)*sum)
+	denom := new(big.Float).SetPrec(prec).Mul(two, sqrt2)
+	denom.Mul(denom, sum)
+	pi := new(big.Float).SetPrec(prec).Quo(nineThousandEightOhOne, denom)
+
+	return pi.Text('f', int(digits)), nil
+}
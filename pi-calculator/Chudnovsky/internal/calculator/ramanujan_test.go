@@ -0,0 +1,52 @@
+package calculator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+func TestRamanujanCalculator_ComputePi(t *testing.T) {
+	cfg := config.Default()
+	calc := NewRamanujanCalculator(cfg)
+
+	piStr, err := calc.ComputePi(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(piStr, knownPiPrefix) {
+		t.Errorf("Expected prefix %q, got %q", knownPiPrefix, piStr)
+	}
+}
+
+func TestRamanujanCalculator_Name(t *testing.T) {
+	calc := NewRamanujanCalculator(config.Default())
+	if calc.Name() != "ramanujan" {
+		t.Errorf("Expected name 'ramanujan', got %q", calc.Name())
+	}
+}
+
+func TestRamanujanCalculator_InvalidInput(t *testing.T) {
+	cfg := config.Default()
+	calc := NewRamanujanCalculator(cfg)
+	ctx := context.Background()
+
+	if _, err := calc.ComputePi(ctx, 0); err == nil {
+		t.Error("Expected error for zero digits")
+	}
+	if _, err := calc.ComputePi(ctx, cfg.MaxDigits+1); err == nil {
+		t.Error("Expected error for exceeding max digits")
+	}
+}
+
+func TestRamanujanCalculator_ContextCancellation(t *testing.T) {
+	calc := NewRamanujanCalculator(config.Default())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := calc.ComputePi(ctx, 1000); err == nil {
+		t.Error("Expected error due to context cancellation")
+	}
+}
@@ -0,0 +1,120 @@
+package calculator
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// checkpointChunk is the on-disk record for one completed Result within a
+// SaveCheckpoint snapshot. SHA256 is computed over the chunk's range and
+// serialized P, Q, T so LoadCheckpoint can detect a chunk that was only
+// partially written, the same concern checksumFor addresses for
+// FileCheckpointStore's WAL entries but checked independently here since a
+// snapshot is a single rewritten file rather than an append-only log.
+type checkpointChunk struct {
+	Range   [2]int64
+	P, Q, T *big.Int
+	SHA256  [32]byte
+}
+
+// sha256ForChunk hashes the fields of a checkpointChunk that determine its
+// content, mirroring checksumFor's nil-safe big.Int rendering.
+func sha256ForChunk(rng [2]int64, p, q, t *big.Int) [32]byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:%d:%s:%s:%s", rng[0], rng[1], bigIntString(p), bigIntString(q), bigIntString(t))
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// checkpointSnapshot is the gob-encoded payload written by SaveCheckpoint.
+type checkpointSnapshot struct {
+	Chunks      []checkpointChunk
+	Cfg         config.Config
+	Fingerprint [32]byte
+}
+
+// fingerprintFor hashes the snapshot's chunk hashes together with the
+// config and algorithm-constant values (DigitsPerTerm, A, B, C3) that
+// determine what those chunks mean, so a snapshot that was truncated,
+// reordered, or saved against a different build's constants is rejected by
+// LoadCheckpoint instead of being silently resumed into an incompatible run.
+func fingerprintFor(chunks []checkpointChunk, cfg config.Config) [32]byte {
+	h := sha256.New()
+	for _, c := range chunks {
+		h.Write(c.SHA256[:])
+	}
+	fmt.Fprintf(h, "digits-per-term:%d:A:%s:B:%s:C3:%s", cfg.DigitsPerTerm, A.String(), B.String(), C3.String())
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// SaveCheckpoint writes a one-shot snapshot of results to path: the
+// completed [a,b) subranges with their P, Q, T values, a SHA-256 over each
+// chunk's serialized big.Ints, and a fingerprint tying the snapshot to the
+// config and algorithm constants it was produced under. Unlike
+// FileCheckpointStore's append-only WAL, which is consulted mid-computation
+// via CheckpointStore.Completed, this is meant for an explicit export of a
+// partial computation's state (or a one-off resume file) rather than a
+// store threaded through ComputePQT.
+func SaveCheckpoint(path string, results []config.Result, cfg *config.Config) error {
+	chunks := make([]checkpointChunk, len(results))
+	for i, r := range results {
+		chunks[i] = checkpointChunk{Range: r.Range, P: r.P, Q: r.Q, T: r.T, SHA256: sha256ForChunk(r.Range, r.P, r.Q, r.T)}
+	}
+
+	snapshot := checkpointSnapshot{Chunks: chunks, Cfg: *cfg, Fingerprint: fingerprintFor(chunks, *cfg)}
+
+	// #nosec G304 -- path is operator-controlled
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint snapshot: %w", err)
+	}
+	defer f.Close()
+
+	if err := gob.NewEncoder(f).Encode(snapshot); err != nil {
+		return fmt.Errorf("failed to encode checkpoint snapshot: %w", err)
+	}
+	return f.Sync()
+}
+
+// LoadCheckpoint reads a snapshot written by SaveCheckpoint. It recomputes
+// every chunk's SHA-256 and the snapshot's overall fingerprint, returning an
+// error if either fails to match rather than handing back partially
+// trustworthy results; a caller that gets an error should treat the
+// snapshot as unusable and recompute from scratch.
+func LoadCheckpoint(path string) ([]config.Result, *config.Config, error) {
+	// #nosec G304 -- path is operator-controlled
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open checkpoint snapshot: %w", err)
+	}
+	defer f.Close()
+
+	var snapshot checkpointSnapshot
+	if err := gob.NewDecoder(f).Decode(&snapshot); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode checkpoint snapshot: %w", err)
+	}
+
+	for _, c := range snapshot.Chunks {
+		if c.SHA256 != sha256ForChunk(c.Range, c.P, c.Q, c.T) {
+			return nil, nil, fmt.Errorf("checkpoint snapshot %s: chunk [%d,%d) failed integrity check", path, c.Range[0], c.Range[1])
+		}
+	}
+	if snapshot.Fingerprint != fingerprintFor(snapshot.Chunks, snapshot.Cfg) {
+		return nil, nil, fmt.Errorf("checkpoint snapshot %s: fingerprint mismatch, snapshot is corrupt or truncated", path)
+	}
+
+	results := make([]config.Result, len(snapshot.Chunks))
+	for i, c := range snapshot.Chunks {
+		results[i] = config.Result{P: c.P, Q: c.Q, T: c.T, Range: c.Range}
+	}
+	cfg := snapshot.Cfg
+	return results, &cfg, nil
+}
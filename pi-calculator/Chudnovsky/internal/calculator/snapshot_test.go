@@ -0,0 +1,74 @@
+package calculator
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+func TestSaveAndLoadCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.gob")
+	cfg := config.Default()
+
+	results := []config.Result{
+		{P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0), Range: [2]int64{0, 1}},
+		{P: big.NewInt(5), Q: big.NewInt(7), T: big.NewInt(11), Range: [2]int64{1, 2}},
+	}
+
+	if err := SaveCheckpoint(path, results, cfg); err != nil {
+		t.Fatalf("Unexpected error saving checkpoint: %v", err)
+	}
+
+	loaded, loadedCfg, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Unexpected error loading checkpoint: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(loaded))
+	}
+	if loaded[1].Q.Cmp(big.NewInt(7)) != 0 {
+		t.Errorf("Expected Q=7 for second chunk, got %s", loaded[1].Q.String())
+	}
+	if loaded[1].Range != [2]int64{1, 2} {
+		t.Errorf("Expected Range [1,2) for second chunk, got %v", loaded[1].Range)
+	}
+	if loadedCfg.DigitsPerTerm != cfg.DigitsPerTerm {
+		t.Errorf("Expected loaded config to match saved config, got DigitsPerTerm=%d", loadedCfg.DigitsPerTerm)
+	}
+}
+
+func TestLoadCheckpoint_CorruptChunkIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.gob")
+	cfg := config.Default()
+
+	results := []config.Result{
+		{P: big.NewInt(1), Q: big.NewInt(1), T: big.NewInt(0), Range: [2]int64{0, 1}},
+	}
+	if err := SaveCheckpoint(path, results, cfg); err != nil {
+		t.Fatalf("Unexpected error saving checkpoint: %v", err)
+	}
+
+	// #nosec G304 -- path is a t.TempDir() file under test control
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error reading checkpoint file: %v", err)
+	}
+	raw[len(raw)-1] ^= 0xFF
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatalf("Unexpected error corrupting checkpoint file: %v", err)
+	}
+
+	if _, _, err := LoadCheckpoint(path); err == nil {
+		t.Error("Expected corrupted checkpoint to be rejected")
+	}
+}
+
+func TestLoadCheckpoint_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist.gob")
+	if _, _, err := LoadCheckpoint(path); err == nil {
+		t.Error("Expected error loading a missing checkpoint file")
+	}
+}
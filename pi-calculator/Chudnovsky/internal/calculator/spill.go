@@ -0,0 +1,259 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"syscall"
+)
+
+// SpillingBigInt holds a *big.Int that is either kept resident in memory
+// or, once its byte representation grows past ThresholdBytes, written out
+// to a memory-mapped temp file so it no longer counts against the
+// process's resident set while it sits idle between combine steps.
+//
+// This spills the *at-rest* representation between operations; it does
+// not give math/big itself a disk-backed limb store; big.Int's internal
+// word slice isn't pluggable, so an operation that needs the value (Mul,
+// Add, ...) still has to materialize it back into memory first. That's
+// enough to bound peak resident memory for the large intermediate P/Q/T
+// results sitting in a CombineResults binary-splitting tree while only
+// a handful of them are actively being multiplied at once; a true
+// disk-backed arithmetic library (arbitrary-precision math performed
+// directly against mmap'd limbs) would be its own arith.Backend.
+type SpillingBigInt struct {
+	threshold int64
+
+	// resident holds the value when it's small enough to keep in memory.
+	resident *big.Int
+
+	// Below are only set once the value has been spilled to disk.
+	file *os.File
+	mmap []byte
+	neg  bool
+	size int // byte length of the spilled magnitude
+}
+
+// DefaultSpillThresholdBytes is the in-memory size (8 GiB) past which
+// NewSpillingBigInt spills a value to disk by default.
+const DefaultSpillThresholdBytes = 8 << 30
+
+// NewSpillingBigInt wraps v, spilling it to a memory-mapped temp file
+// immediately if its byte representation already exceeds thresholdBytes.
+func NewSpillingBigInt(v *big.Int, thresholdBytes int64) (*SpillingBigInt, error) {
+	s := &SpillingBigInt{threshold: thresholdBytes}
+	if err := s.set(v); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// set stores v, spilling to disk if it exceeds s.threshold. Any
+// previously spilled file is released first.
+func (s *SpillingBigInt) set(v *big.Int) error {
+	if err := s.releaseSpill(); err != nil {
+		return err
+	}
+
+	bytes := v.Bytes()
+	if int64(len(bytes)) <= s.threshold {
+		s.resident = v
+		return nil
+	}
+
+	f, err := os.CreateTemp("", "chudnovsky-spill-*.bin")
+	if err != nil {
+		return fmt.Errorf("failed to create spill file: %w", err)
+	}
+	if err := f.Truncate(int64(len(bytes))); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to size spill file: %w", err)
+	}
+
+	mapped, err := syscall.Mmap(int(f.Fd()), 0, len(bytes), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return fmt.Errorf("failed to mmap spill file: %w", err)
+	}
+	copy(mapped, bytes)
+
+	s.file = f
+	s.mmap = mapped
+	s.neg = v.Sign() < 0
+	s.size = len(bytes)
+	s.resident = nil
+	return nil
+}
+
+// releaseSpill unmaps and removes any on-disk spill file, leaving s ready
+// to hold a new value.
+func (s *SpillingBigInt) releaseSpill() error {
+	if s.mmap == nil {
+		return nil
+	}
+	name := s.file.Name()
+	err := syscall.Munmap(s.mmap)
+	s.mmap = nil
+	closeErr := s.file.Close()
+	s.file = nil
+	removeErr := os.Remove(name)
+	if err != nil {
+		return fmt.Errorf("failed to munmap spill file: %w", err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close spill file: %w", closeErr)
+	}
+	if removeErr != nil {
+		return fmt.Errorf("failed to remove spill file: %w", removeErr)
+	}
+	return nil
+}
+
+// Int materializes the wrapped value back into memory as a *big.Int.
+func (s *SpillingBigInt) Int() *big.Int {
+	if s.resident != nil {
+		return s.resident
+	}
+	v := new(big.Int).SetBytes(s.mmap)
+	if s.neg {
+		v.Neg(v)
+	}
+	return v
+}
+
+// Close releases the on-disk spill file, if one was created. Safe to
+// call on a SpillingBigInt that never spilled.
+func (s *SpillingBigInt) Close() error {
+	return s.releaseSpill()
+}
+
+// spillingResult is a Result whose P, Q, T are held as SpillingBigInts
+// instead of plain *big.Int, so a subtree combined lower in the recursion
+// can be handed back up and left spilled to disk for however long its
+// sibling subtree takes to combine, instead of sitting resident the
+// moment it's produced. Close must be called once the caller is done
+// with it (directly, or via combineSpillingPair/CombineResultsSpillingCtx
+// folding it into a parent).
+type spillingResult struct {
+	P, Q, T *SpillingBigInt
+}
+
+// newSpillingResult spills r's P, Q, T (if they're large enough to cross
+// thresholdBytes).
+func newSpillingResult(r Result, thresholdBytes int64) (*spillingResult, error) {
+	p, err := NewSpillingBigInt(r.P, thresholdBytes)
+	if err != nil {
+		return nil, err
+	}
+	q, err := NewSpillingBigInt(r.Q, thresholdBytes)
+	if err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	t, err := NewSpillingBigInt(r.T, thresholdBytes)
+	if err != nil {
+		_ = p.Close()
+		_ = q.Close()
+		return nil, err
+	}
+	return &spillingResult{P: p, Q: q, T: t}, nil
+}
+
+// Close releases every spill file this result holds.
+func (r *spillingResult) Close() error {
+	err := r.P.Close()
+	if qErr := r.Q.Close(); err == nil {
+		err = qErr
+	}
+	if tErr := r.T.Close(); err == nil {
+		err = tErr
+	}
+	return err
+}
+
+// CombineResultsSpilling is CombineResultsSpillingCtx with a
+// non-cancellable context, for callers that don't need cancellation.
+//
+//nolint:gocritic // P, Q, T are exported return values, capitalization is intentional
+func CombineResultsSpilling(results []Result, thresholdBytes int64) (P, Q, T *big.Int, err error) {
+	return CombineResultsSpillingCtx(context.Background(), results, thresholdBytes)
+}
+
+// CombineResultsSpillingCtx is CombineResultsCtx, but each subtree's
+// combined P, Q, T is kept as a SpillingBigInt rather than a resident
+// *big.Int across the recursion: a left subtree's result stays spilled to
+// disk for as long as its right sibling takes to combine, and each pair's
+// inputs are rematerialized only for the duration of their own Mul/Add
+// before the combined output is itself re-spilled. This bounds how many
+// full-size P/Q/T values are resident at once to roughly the recursion
+// depth rather than the whole tree, so a binary-splitting combine over
+// billions of digits can run on modest RAM. thresholdBytes of 0 uses
+// DefaultSpillThresholdBytes.
+//
+//nolint:gocritic // P, Q, T are exported return values, capitalization is intentional
+func CombineResultsSpillingCtx(ctx context.Context, results []Result, thresholdBytes int64) (P, Q, T *big.Int, err error) {
+	if thresholdBytes <= 0 {
+		thresholdBytes = DefaultSpillThresholdBytes
+	}
+
+	root, err := combineResultsSpillingCtx(ctx, results, thresholdBytes)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer func() { _ = root.Close() }()
+	return root.P.Int(), root.Q.Int(), root.T.Int(), nil
+}
+
+// combineResultsSpillingCtx is CombineResultsSpillingCtx's recursive
+// worker, returning the still-spilled subtree result rather than
+// rematerializing it, so the caller decides when (if ever) to bring it
+// back into memory.
+func combineResultsSpillingCtx(ctx context.Context, results []Result, thresholdBytes int64) (*spillingResult, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	if len(results) == 1 {
+		return newSpillingResult(results[0], thresholdBytes)
+	}
+
+	mid := len(results) / 2
+	left, err := combineResultsSpillingCtx(ctx, results[:mid], thresholdBytes)
+	if err != nil {
+		return nil, err
+	}
+	right, err := combineResultsSpillingCtx(ctx, results[mid:], thresholdBytes)
+	if err != nil {
+		_ = left.Close()
+		return nil, err
+	}
+	return combinePairSpilling(left, right, thresholdBytes)
+}
+
+// combinePairSpilling merges two still-spilled subtree results,
+// rematerializing each input only long enough to run Mul/Add, then
+// spills the combined output before returning it. left and right are
+// closed by a deferred call once the output has been spilled, so neither
+// lingers resident (or on disk) past this merge.
+func combinePairSpilling(left, right *spillingResult, thresholdBytes int64) (*spillingResult, error) {
+	defer func() {
+		_ = left.Close()
+		_ = right.Close()
+	}()
+
+	lp, lq, lt := left.P.Int(), left.Q.Int(), left.T.Int()
+	rp, rq, rt := right.P.Int(), right.Q.Int(), right.T.Int()
+
+	p := new(big.Int).Mul(lp, rp)
+	q := new(big.Int).Mul(lq, rq)
+	t := new(big.Int).Add(
+		new(big.Int).Mul(rq, lt),
+		new(big.Int).Mul(lp, rt),
+	)
+	return newSpillingResult(Result{P: p, Q: q, T: t}, thresholdBytes)
+}
@@ -0,0 +1,101 @@
+package calculator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/workerpool"
+)
+
+func TestSpillingBigInt_SmallValueStaysResident(t *testing.T) {
+	v := big.NewInt(12345)
+	s, err := NewSpillingBigInt(v, DefaultSpillThresholdBytes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer s.Close()
+
+	if s.mmap != nil {
+		t.Error("Expected small value to stay resident, not spill to disk")
+	}
+	if s.Int().Cmp(v) != 0 {
+		t.Errorf("Expected Int() to return %s, got %s", v, s.Int())
+	}
+}
+
+func TestSpillingBigInt_LargeValueSpillsAndRoundTrips(t *testing.T) {
+	// A value whose byte length exceeds a tiny threshold should spill to
+	// a memory-mapped temp file and still round-trip correctly.
+	v := new(big.Int).Lsh(big.NewInt(1), 4096) // 512+ bytes
+	v.Sub(v, big.NewInt(1))
+
+	s, err := NewSpillingBigInt(v, 64)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer s.Close()
+
+	if s.mmap == nil {
+		t.Fatal("Expected large value to spill to disk")
+	}
+	if s.Int().Cmp(v) != 0 {
+		t.Errorf("Expected round-tripped value to match original")
+	}
+}
+
+func TestSpillingBigInt_NegativeValueRoundTrips(t *testing.T) {
+	v := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 4096))
+
+	s, err := NewSpillingBigInt(v, 64)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer s.Close()
+
+	if s.Int().Cmp(v) != 0 {
+		t.Errorf("Expected round-tripped negative value to match original, got %s", s.Int())
+	}
+}
+
+func TestCombineResultsSpilling_MatchesCombineResults(t *testing.T) {
+	results := []Result{
+		{P: big.NewInt(2), Q: big.NewInt(3), T: big.NewInt(5)},
+		{P: big.NewInt(7), Q: big.NewInt(11), T: big.NewInt(13)},
+		{P: big.NewInt(17), Q: big.NewInt(19), T: big.NewInt(23)},
+		{P: big.NewInt(29), Q: big.NewInt(31), T: big.NewInt(37)},
+	}
+
+	wantP, wantQ, wantT := CombineResults(results)
+
+	// A 1-byte threshold forces every value wider than one byte through
+	// the spill path, exercising it even for these small numbers.
+	gotP, gotQ, gotT, err := CombineResultsSpilling(results, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gotP.Cmp(wantP) != 0 || gotQ.Cmp(wantQ) != 0 || gotT.Cmp(wantT) != 0 {
+		t.Errorf("Expected spilling combine to match CombineResults: got (%s,%s,%s) want (%s,%s,%s)",
+			gotP, gotQ, gotT, wantP, wantQ, wantT)
+	}
+}
+
+func TestComputePQTParallel_SpillThresholdBytesMatchesSequential(t *testing.T) {
+	cfg := config.Default()
+	cfg.SpillThresholdBytes = 1 // force every intermediate value through the spill path
+	pool := workerpool.New(4)
+	defer pool.Close()
+	ctx := context.Background()
+
+	P, Q, T, err := ComputePQT(ctx, 0, 2000, cfg, pool)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	wantP, wantQ, wantT := ComputePQTSequential(0, 2000)
+	if P.Cmp(wantP) != 0 || Q.Cmp(wantQ) != 0 || T.Cmp(wantT) != 0 {
+		t.Error("Expected SpillThresholdBytes to route through CombineResultsSpillingCtx without changing the result")
+	}
+}
@@ -0,0 +1,257 @@
+package calculator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/verifier"
+)
+
+// hexBitsPerDigit is the number of bits contributed per decimal digit,
+// matching config.Config.BitsPerDigit; four bits make one hex digit.
+const hexBitsPerDigit = 3.322
+
+// verifyWindowSize is the number of trailing hex digits cross-checked by
+// Verify. A small window is enough to catch the classes of corruption this
+// guards against (mis-combined subtrees, precision miscalculation) without
+// paying the cost of converting the whole result to hex.
+const verifyWindowSize = 16
+
+// verifyTrailingGuardHexDigits is the number of hex digits trimmed off the
+// far end of piStr's available precision before deriving a window or
+// sample positions. ComputePi's prec carries no guard bits, so the very
+// last hex digits of its decimal output are only as reliable as the final
+// rounding of the Quo/Sqrt chain and the decimal-to-hex base conversion
+// allow; comparing them against BBP's exact digits produces false
+// mismatches on otherwise-correct output. Staying this far back from the
+// edge keeps Verify/VerifySample trustworthy without requiring ComputePi
+// itself to carry extra precision.
+const verifyTrailingGuardHexDigits = 8
+
+// VerifyResult reports the outcome of cross-checking a window of hex
+// digits derived from the Chudnovsky decimal result against independently
+// computed BBP hex digits.
+type VerifyResult struct {
+	// WindowStart is the 0-indexed hex-digit position (after "3.") where
+	// the checked window begins.
+	WindowStart int64
+
+	// BBPDigits are the hex digits computed independently via BBP.
+	BBPDigits []byte
+
+	// DecimalDigits are the hex digits derived from the Chudnovsky
+	// decimal string, at the same positions.
+	DecimalDigits []byte
+
+	// FirstMismatch is the offset within the window of the first digit
+	// where BBPDigits and DecimalDigits disagree, or -1 if none.
+	FirstMismatch int
+}
+
+// VerifyHexDigit returns the hexadecimal digit of π at 0-indexed position n
+// after the point, computed independently via the Bailey–Borwein–Plouffe
+// formula rather than derived from a Chudnovsky decimal result. Exposed
+// alongside ComputePi so callers can spot-check an arbitrary position
+// without running a full Verify pass over a decimal string. Uses
+// verifier.HexDigitParallel, since a single large n is exactly the case
+// the k-loop fan-out is worth paying for.
+func VerifyHexDigit(ctx context.Context, n int64) (byte, error) {
+	return verifier.HexDigitParallel(ctx, n)
+}
+
+// Verify cross-checks a window of up to verifyWindowSize hexadecimal
+// digits, ending verifyTrailingGuardHexDigits before the last hex digit
+// the decimal precision covers, of a
+// Chudnovsky-computed π string against independently computed BBP hex
+// digits, to catch subtle corruption (mis-combined subtrees, precision
+// miscalculation) that wouldn't be visible from the decimal output alone.
+//
+// piStr is the decimal π string as returned by ComputePi (e.g. "3.14159...");
+// digits is the number of decimal digits it contains after the point.
+func Verify(ctx context.Context, piStr string, digits int64) (*VerifyResult, error) {
+	if digits < 1 {
+		return nil, fmt.Errorf("digits must be at least 1, got %d", digits)
+	}
+
+	totalHex := int64(float64(digits)*hexBitsPerDigit/4) - verifyTrailingGuardHexDigits
+	if totalHex < 1 {
+		return nil, fmt.Errorf("not enough digits to derive a reliable hex window")
+	}
+
+	windowSize := int64(verifyWindowSize)
+	if windowSize > totalHex {
+		windowSize = totalHex
+	}
+	start := totalHex - windowSize
+
+	prec := uint(float64(digits)*hexBitsPerDigit) + 64
+	decimalDigits, err := decimalToHexWindow(piStr, prec, start, windowSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive hex window from decimal result: %w", err)
+	}
+
+	bbpDigits := make([]byte, windowSize)
+	for i := int64(0); i < windowSize; i++ {
+		d, err := verifier.HexDigit(ctx, start+i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute BBP hex digit at position %d: %w", start+i, err)
+		}
+		bbpDigits[i] = d
+	}
+
+	result := &VerifyResult{
+		WindowStart:   start,
+		BBPDigits:     bbpDigits,
+		DecimalDigits: decimalDigits,
+		FirstMismatch: -1,
+	}
+	for i := range bbpDigits {
+		if bbpDigits[i] != decimalDigits[i] {
+			result.FirstMismatch = i
+			break
+		}
+	}
+	return result, nil
+}
+
+// SampleVerifyResult reports the outcome of spot-checking a set of
+// uniformly random hex-digit positions against independently computed BBP
+// hex digits.
+type SampleVerifyResult struct {
+	// Positions are the 0-indexed hex-digit positions sampled, in
+	// ascending order.
+	Positions []int64
+
+	// BBPDigits are the hex digits computed independently via BBP, one
+	// per Positions entry.
+	BBPDigits []byte
+
+	// DecimalDigits are the hex digits derived from the Chudnovsky
+	// decimal string, at the same positions.
+	DecimalDigits []byte
+
+	// FirstMismatch is the index into Positions of the first position
+	// where BBPDigits and DecimalDigits disagree, or -1 if none.
+	FirstMismatch int
+}
+
+// VerifySample spot-checks k uniformly random hex-digit positions spread
+// across the entire computed result, unlike Verify's fixed trailing
+// window. This catches corruption anywhere in a billion-digit run - a
+// mis-combined subtree near the start is just as likely to be sampled as
+// one near the end - at a cost that scales with k rather than with
+// digits. rng lets callers (tests, or anyone wanting reproducible
+// sampling) supply a seeded source; pass nil to seed from the current
+// time.
+func VerifySample(ctx context.Context, piStr string, digits int64, k int, rng *rand.Rand) (*SampleVerifyResult, error) {
+	if digits < 1 {
+		return nil, fmt.Errorf("digits must be at least 1, got %d", digits)
+	}
+	if k < 1 {
+		return nil, fmt.Errorf("sample size must be at least 1, got %d", k)
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	totalHex := int64(float64(digits)*hexBitsPerDigit/4) - verifyTrailingGuardHexDigits
+	if totalHex < 1 {
+		return nil, fmt.Errorf("not enough digits to sample a reliable hex position")
+	}
+	if int64(k) > totalHex {
+		k = int(totalHex)
+	}
+
+	positions := make([]int64, k)
+	for i := range positions {
+		positions[i] = rng.Int63n(totalHex)
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+
+	prec := uint(float64(digits)*hexBitsPerDigit) + 64
+	decimalDigits, err := decimalToHexAtPositions(piStr, prec, positions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive hex digits from decimal result: %w", err)
+	}
+
+	bbpDigits := make([]byte, k)
+	for i, pos := range positions {
+		d, err := verifier.HexDigit(ctx, pos)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute BBP hex digit at position %d: %w", pos, err)
+		}
+		bbpDigits[i] = d
+	}
+
+	result := &SampleVerifyResult{
+		Positions:     positions,
+		BBPDigits:     bbpDigits,
+		DecimalDigits: decimalDigits,
+		FirstMismatch: -1,
+	}
+	for i := range bbpDigits {
+		if bbpDigits[i] != decimalDigits[i] {
+			result.FirstMismatch = i
+			break
+		}
+	}
+	return result, nil
+}
+
+// decimalToHexAtPositions converts the fractional part of piStr to hex
+// digit-by-digit in a single left-to-right pass (the same technique as
+// decimalToHexWindow) and collects the digit at each of positions (which
+// must be sorted ascending), so scattered samples share one pass instead
+// of re-converting from the start for each.
+func decimalToHexAtPositions(piStr string, prec uint, positions []int64) ([]byte, error) {
+	f, ok := new(big.Float).SetPrec(prec).SetString(piStr)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal pi string")
+	}
+
+	frac := new(big.Float).SetPrec(prec).Sub(f, new(big.Float).SetPrec(prec).SetInt64(3))
+	sixteen := new(big.Float).SetPrec(prec).SetInt64(16)
+
+	digits := make([]byte, len(positions))
+	next := 0
+	last := positions[len(positions)-1]
+	for i := int64(0); i <= last; i++ {
+		frac.Mul(frac, sixteen)
+		intPart, _ := frac.Int(nil)
+		frac.Sub(frac, new(big.Float).SetPrec(prec).SetInt(intPart))
+		for next < len(positions) && positions[next] == i {
+			digits[next] = byte(intPart.Int64())
+			next++
+		}
+	}
+	return digits, nil
+}
+
+// decimalToHexWindow converts the fractional part of the decimal π string
+// to hexadecimal digit-by-digit (via repeated multiply-by-16-and-floor on a
+// big.Float of the requested precision) and returns the count digits
+// starting at the given 0-indexed hex position.
+func decimalToHexWindow(piStr string, prec uint, start, count int64) ([]byte, error) {
+	f, ok := new(big.Float).SetPrec(prec).SetString(piStr)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal pi string")
+	}
+
+	frac := new(big.Float).SetPrec(prec).Sub(f, new(big.Float).SetPrec(prec).SetInt64(3))
+	sixteen := new(big.Float).SetPrec(prec).SetInt64(16)
+
+	digits := make([]byte, 0, count)
+	for i := int64(0); i < start+count; i++ {
+		frac.Mul(frac, sixteen)
+		intPart, _ := frac.Int(nil)
+		frac.Sub(frac, new(big.Float).SetPrec(prec).SetInt(intPart))
+		if i >= start {
+			digits = append(digits, byte(intPart.Int64()))
+		}
+	}
+	return digits, nil
+}
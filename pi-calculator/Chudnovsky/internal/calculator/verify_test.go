@@ -0,0 +1,130 @@
+package calculator
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+func TestVerify_MatchesKnownPi(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default()
+	calc := New(cfg, nil)
+
+	piStr, err := calc.ComputePi(ctx, 200)
+	if err != nil {
+		t.Fatalf("Unexpected error computing pi: %v", err)
+	}
+
+	result, err := Verify(ctx, piStr, 200)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.FirstMismatch != -1 {
+		t.Errorf("Expected no mismatch, found one at offset %d (bbp=%x decimal=%x)",
+			result.FirstMismatch, result.BBPDigits[result.FirstMismatch], result.DecimalDigits[result.FirstMismatch])
+	}
+}
+
+func TestVerify_InvalidDigits(t *testing.T) {
+	_, err := Verify(context.Background(), "3.14", 0)
+	if err == nil {
+		t.Error("Expected error for non-positive digits")
+	}
+}
+
+func TestVerify_InvalidPiString(t *testing.T) {
+	_, err := Verify(context.Background(), "not-a-number", 50)
+	if err == nil {
+		t.Error("Expected error for unparsable pi string")
+	}
+}
+
+func TestVerifyHexDigit_KnownValues(t *testing.T) {
+	ctx := context.Background()
+
+	// The hex expansion of pi is 3.243F6A8885A308D3...
+	digit, err := VerifyHexDigit(ctx, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if digit != 0x2 {
+		t.Errorf("Expected hex digit 0x2 at position 0, got 0x%x", digit)
+	}
+}
+
+func TestVerifyHexDigit_InvalidPosition(t *testing.T) {
+	_, err := VerifyHexDigit(context.Background(), -1)
+	if err == nil {
+		t.Error("Expected error for negative position")
+	}
+}
+
+func TestVerifySample_MatchesKnownPi(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default()
+	calc := New(cfg, nil)
+
+	piStr, err := calc.ComputePi(ctx, 200)
+	if err != nil {
+		t.Fatalf("Unexpected error computing pi: %v", err)
+	}
+
+	result, err := VerifySample(ctx, piStr, 200, 16, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Positions) != 16 {
+		t.Errorf("Expected 16 sampled positions, got %d", len(result.Positions))
+	}
+	if result.FirstMismatch != -1 {
+		idx := result.FirstMismatch
+		t.Errorf("Expected no mismatch, found one at position %d (bbp=%x decimal=%x)",
+			result.Positions[idx], result.BBPDigits[idx], result.DecimalDigits[idx])
+	}
+}
+
+func TestVerifySample_DeterministicWithSeededRand(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Default()
+	calc := New(cfg, nil)
+
+	piStr, err := calc.ComputePi(ctx, 200)
+	if err != nil {
+		t.Fatalf("Unexpected error computing pi: %v", err)
+	}
+
+	a, err := VerifySample(ctx, piStr, 200, 8, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	b, err := VerifySample(ctx, piStr, 200, 8, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(a.Positions) != len(b.Positions) {
+		t.Fatalf("Expected matching sample sizes, got %d and %d", len(a.Positions), len(b.Positions))
+	}
+	for i := range a.Positions {
+		if a.Positions[i] != b.Positions[i] {
+			t.Errorf("Expected the same seed to sample the same positions, got %v and %v", a.Positions, b.Positions)
+			break
+		}
+	}
+}
+
+func TestVerifySample_InvalidDigits(t *testing.T) {
+	_, err := VerifySample(context.Background(), "3.14", 0, 4, nil)
+	if err == nil {
+		t.Error("Expected error for non-positive digits")
+	}
+}
+
+func TestVerifySample_InvalidSampleSize(t *testing.T) {
+	_, err := VerifySample(context.Background(), "3.14", 200, 0, nil)
+	if err == nil {
+		t.Error("Expected error for non-positive sample size")
+	}
+}
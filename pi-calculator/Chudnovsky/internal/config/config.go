@@ -17,6 +17,14 @@ type Config struct {
 	// MinRangeForWorkerPool is the minimum range size to use worker pool
 	MinRangeForWorkerPool int64
 
+	// StealThreshold is the minimum [a,b) range size still worth splitting
+	// into two recursive halves when using a work-stealing pool
+	// (workerpool.StealPool); ranges at or below it are computed inline
+	// with ComputePQTSequentialCtx instead, since the overhead of pushing
+	// a task and waiting on its result channel stops paying for itself
+	// well before MinRangeForWorkerPool's granularity.
+	StealThreshold int64
+
 	// DigitsPerTerm is the approximate digits per term in Chudnovsky algorithm
 	DigitsPerTerm int64
 
@@ -25,6 +33,13 @@ type Config struct {
 
 	// ProgressBarEnabled controls whether to show progress bar
 	ProgressBarEnabled bool
+
+	// SpillThresholdBytes, if non-zero, makes computePQTParallel combine
+	// its chunk results via calculator.CombineResultsSpillingCtx instead
+	// of CombineResultsCtx, spilling any intermediate P/Q/T value larger
+	// than this many bytes to disk while it sits idle between combine
+	// steps. 0 disables spilling.
+	SpillThresholdBytes int64
 }
 
 // Default returns the default configuration with sensible values.
@@ -35,6 +50,7 @@ func Default() *Config {
 		WorkerPoolSize:        0,          // Auto-detect
 		MaxChunkSize:          500,
 		MinRangeForWorkerPool: 1000,
+		StealThreshold:        250,
 		DigitsPerTerm:         14,
 		BitsPerDigit:          3.322,
 		ProgressBarEnabled:    true,
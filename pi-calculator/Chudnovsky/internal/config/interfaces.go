@@ -21,8 +21,17 @@ type ProgressReporter interface {
 
 // Result represents a PQT computation result from the Chudnovsky algorithm.
 // P, Q, and T are the three values computed for each term in the series.
+// Range is the [a,b) subrange of terms this result covers, populated by
+// producers that know it (chunked and recursive parallel computation); it
+// is the zero value ([0,0]) for results, such as a final combined result,
+// that aren't tied to a single contiguous subrange. Err is set when the
+// computation that produced this Result failed (for example because its
+// context was cancelled partway through); P, Q, and T are undefined when
+// Err is non-nil.
 type Result struct {
 	P, Q, T *big.Int
+	Range   [2]int64
+	Err     error
 }
 
 // PQTComputer defines the interface for computing PQT values.
@@ -0,0 +1,325 @@
+package formatter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultDigitsPerLine matches the grouping used by FormatPiOutput.
+const defaultDigitsPerLine = 50
+
+// Encoder writes a computed π value to w in some on-disk representation.
+// Implementations must stream from piStr rather than buffering the whole
+// encoded output in memory, so multi-gigabyte results don't require a
+// second copy of the data to be held alongside piStr.
+type Encoder interface {
+	Encode(w io.Writer, digits int, piStr string) error
+}
+
+// splitPiString separates a "3.14159..." result into its integer part and
+// the digits after the decimal point, tolerating the same irregular inputs
+// FormatPiOutput has always had to handle (no decimal point, too short to
+// contain one, etc).
+func splitPiString(piStr string) (integerPart, fraction string) {
+	if strings.Contains(piStr, ".") {
+		parts := strings.SplitN(piStr, ".", 2)
+		return parts[0], parts[1]
+	}
+	if len(piStr) > 0 {
+		return piStr[:1], piStr[1:]
+	}
+	return "", ""
+}
+
+// digitLabel renders the digit-count header line shared by the text and
+// paged encoders (e.g. "1000 Digits of Pi", "1 Million Digits of Pi").
+func digitLabel(digits int) string {
+	switch {
+	case digits >= 1000000 && digits%1000000 == 0:
+		return fmt.Sprintf("%d Million Digits of Pi", digits/1000000)
+	case digits >= 1000 && digits%1000 == 0:
+		return fmt.Sprintf("%d Thousand Digits of Pi", digits/1000)
+	default:
+		return fmt.Sprintf("%d Digits of Pi", digits)
+	}
+}
+
+// TextEncoder reproduces the human-readable FormatPiOutput layout but
+// streams it directly to w instead of building the whole result in a
+// strings.Builder first.
+type TextEncoder struct {
+	// DigitsPerLine is the number of fractional digits per output line.
+	// Zero means defaultDigitsPerLine.
+	DigitsPerLine int
+}
+
+// Encode implements Encoder.
+func (e TextEncoder) Encode(w io.Writer, digits int, piStr string) error {
+	digitsPerLine := e.DigitsPerLine
+	if digitsPerLine <= 0 {
+		digitsPerLine = defaultDigitsPerLine
+	}
+
+	bw := bufio.NewWriter(w)
+	integerPart, fraction := splitPiString(piStr)
+
+	fmt.Fprintf(bw, "%s\n", digitLabel(digits))
+	fmt.Fprintf(bw, "collected by Vu Hung\n")
+	fmt.Fprintf(bw, "https://github.com/vuhung16au/math-olympiad-ml/tree/main/pi-calculator/Chudnovsky\n\n")
+	fmt.Fprintf(bw, "%s.\n", integerPart)
+
+	for i := 0; i < len(fraction); i += digitsPerLine {
+		end := i + digitsPerLine
+		if end > len(fraction) {
+			end = len(fraction)
+		}
+		if _, err := bw.WriteString(fraction[i:end]); err != nil {
+			return fmt.Errorf("failed to write digit line: %w", err)
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed to write line terminator: %w", err)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// JSONEncoder emits {"algorithm":"chudnovsky","digits":N,"integer":"3","fraction":"..."}.
+// The fraction field is written as a raw JSON string value; because pi's
+// digits never need escaping, this avoids holding a second, json.Marshal'd
+// copy of a multi-gigabyte fraction in memory.
+type JSONEncoder struct{}
+
+// Encode implements Encoder.
+func (e JSONEncoder) Encode(w io.Writer, digits int, piStr string) error {
+	bw := bufio.NewWriter(w)
+	integerPart, fraction := splitPiString(piStr)
+
+	fmt.Fprintf(bw, `{"algorithm":"chudnovsky","digits":%d,"integer":%q,"fraction":"`, digits, integerPart)
+	if _, err := bw.WriteString(fraction); err != nil {
+		return fmt.Errorf("failed to write fraction digits: %w", err)
+	}
+	if _, err := bw.WriteString("\"}\n"); err != nil {
+		return fmt.Errorf("failed to write closing brace: %w", err)
+	}
+
+	return bw.Flush()
+}
+
+// pagedManifestName is the manifest file written alongside the page_NNNN.txt
+// files produced by PagedEncoder.
+const pagedManifestName = "manifest.txt"
+
+// PagedEncoder writes a y-cruncher-style set of page_NNNN.txt files (each
+// holding DigitsPerPage fractional digits) into Dir, plus a manifest.txt
+// recording the digit count, page count and digits per page, so results
+// can be diffed chunk-wise. Encode also writes a digit-count header and
+// one line per page (its file name and digit range) to w, so callers that
+// only care about a single output stream still get useful output.
+type PagedEncoder struct {
+	// Dir is the directory pages are written into. It is the caller's
+	// responsibility to have sanitized it via security.SanitizePath.
+	Dir string
+
+	// DigitsPerPage is the number of fractional digits per page file.
+	DigitsPerPage int
+}
+
+// Encode implements Encoder.
+func (e PagedEncoder) Encode(w io.Writer, digits int, piStr string) error {
+	digitsPerPage := e.DigitsPerPage
+	if digitsPerPage <= 0 {
+		digitsPerPage = 1_000_000
+	}
+
+	if err := os.MkdirAll(e.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create paged output directory: %w", err)
+	}
+
+	_, fraction := splitPiString(piStr)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s\n3.\n\n", digitLabel(digits))
+
+	pageNum := 0
+	for i := 0; i < len(fraction); i += digitsPerPage {
+		end := i + digitsPerPage
+		if end > len(fraction) {
+			end = len(fraction)
+		}
+
+		pageName := fmt.Sprintf("page_%04d.txt", pageNum)
+		// #nosec G304 -- Dir is sanitized by the caller before construction
+		pageFile, err := os.Create(filepath.Join(e.Dir, pageName))
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", pageName, err)
+		}
+		if _, err := pageFile.WriteString(fraction[i:end]); err != nil {
+			_ = pageFile.Close()
+			return fmt.Errorf("failed to write %s: %w", pageName, err)
+		}
+		if err := pageFile.Close(); err != nil {
+			return fmt.Errorf("failed to close %s: %w", pageName, err)
+		}
+
+		fmt.Fprintf(bw, "%s: digits [%d,%d)\n", pageName, i, end)
+		pageNum++
+	}
+
+	manifestPath := filepath.Join(e.Dir, pagedManifestName)
+	// #nosec G304 -- Dir is sanitized by the caller before construction
+	manifestFile, err := os.Create(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	defer manifestFile.Close()
+	if _, err := fmt.Fprintf(manifestFile, "digits=%d pages=%d digits_per_page=%d\n", digits, pageNum, digitsPerPage); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return bw.Flush()
+}
+
+// ycdRadixExponent is the power of ten each .ycd limb is taken modulo;
+// 10^19 is the largest power of ten that still fits in a uint64, which is
+// why y-cruncher's own chunk format uses it.
+const ycdRadixExponent = 19
+
+// YCruncherEncoder writes a y-cruncher-compatible decompressed pi.txt (a
+// "3." header followed by continuous digits wrapped at LineWidth) to w,
+// plus a companion .ycd binary chunk file at YCDPath: a 16-byte header
+// (digit count, then radix exponent, both little-endian uint64) followed
+// by the fractional digits packed as little-endian base-10^19 limbs,
+// least-significant limb first. Existing y-cruncher verification tooling
+// can check our output against this companion file without needing the
+// plain-text digits re-parsed.
+type YCruncherEncoder struct {
+	// LineWidth is the number of fractional digits per line in pi.txt.
+	// Zero means defaultDigitsPerLine.
+	LineWidth int
+
+	// YCDPath is where the companion .ycd file is written. Empty skips
+	// writing it (pi.txt is still produced). It is the caller's
+	// responsibility to have sanitized it via security.SanitizePath.
+	YCDPath string
+}
+
+// Encode implements Encoder.
+func (e YCruncherEncoder) Encode(w io.Writer, digits int, piStr string) error {
+	lineWidth := e.LineWidth
+	if lineWidth <= 0 {
+		lineWidth = defaultDigitsPerLine
+	}
+
+	integerPart, fraction := splitPiString(piStr)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s.\n", integerPart)
+	for i := 0; i < len(fraction); i += lineWidth {
+		end := i + lineWidth
+		if end > len(fraction) {
+			end = len(fraction)
+		}
+		if _, err := bw.WriteString(fraction[i:end]); err != nil {
+			return fmt.Errorf("failed to write digit line: %w", err)
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed to write line terminator: %w", err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	if e.YCDPath == "" {
+		return nil
+	}
+	return writeYCDFile(e.YCDPath, fraction)
+}
+
+// writeYCDFile packs fraction's digits into little-endian base-10^19
+// limbs and writes them, preceded by a digit-count/radix-exponent header,
+// to path.
+func writeYCDFile(path, fraction string) error {
+	// #nosec G304 -- path is sanitized by the caller before construction
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create ycd file: %w", err)
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+
+	header := make([]byte, 16)
+	binary.LittleEndian.PutUint64(header[0:8], uint64(len(fraction)))
+	binary.LittleEndian.PutUint64(header[8:16], ycdRadixExponent)
+	if _, err := bw.Write(header); err != nil {
+		return fmt.Errorf("failed to write ycd header: %w", err)
+	}
+
+	if len(fraction) > 0 {
+		value, ok := new(big.Int).SetString(fraction, 10)
+		if !ok {
+			return fmt.Errorf("invalid fraction digits")
+		}
+
+		radix := new(big.Int).Exp(big.NewInt(10), big.NewInt(ycdRadixExponent), nil)
+		mod := new(big.Int)
+		limb := make([]byte, 8)
+		for value.Sign() > 0 {
+			value.DivMod(value, radix, mod)
+			binary.LittleEndian.PutUint64(limb, mod.Uint64())
+			if _, err := bw.Write(limb); err != nil {
+				return fmt.Errorf("failed to write ycd limb: %w", err)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// binaryMagic identifies the compact binary encoding produced by
+// BinaryEncoder, so a reader can tell it apart from a plain BCD dump.
+const binaryMagic uint32 = 0x50494243 // "PIBC"
+
+// BinaryEncoder packs the fractional digits as packed BCD (two decimal
+// digits per byte), roughly halving file size versus the ASCII text
+// encoding. The format is a small fixed header (magic, digit count)
+// followed by the packed nibbles.
+type BinaryEncoder struct{}
+
+// Encode implements Encoder.
+func (e BinaryEncoder) Encode(w io.Writer, digits int, piStr string) error {
+	bw := bufio.NewWriter(w)
+	_, fraction := splitPiString(piStr)
+
+	header := make([]byte, 12)
+	binary.BigEndian.PutUint32(header[0:4], binaryMagic)
+	binary.BigEndian.PutUint64(header[4:12], uint64(len(fraction)))
+	if _, err := bw.Write(header); err != nil {
+		return fmt.Errorf("failed to write binary header: %w", err)
+	}
+
+	for i := 0; i < len(fraction); i += 2 {
+		hi := fraction[i] - '0'
+		lo := byte(0)
+		if i+1 < len(fraction) {
+			lo = fraction[i+1] - '0'
+		} else {
+			// Odd digit count: pad the low nibble with 0xF so a reader can
+			// distinguish "no digit" from the legitimate digit 0.
+			lo = 0xF
+		}
+		if err := bw.WriteByte(hi<<4 | lo); err != nil {
+			return fmt.Errorf("failed to write packed digit pair: %w", err)
+		}
+	}
+
+	return bw.Flush()
+}
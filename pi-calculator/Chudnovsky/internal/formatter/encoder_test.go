@@ -0,0 +1,134 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTextEncoder_MatchesFormatPiOutput(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (TextEncoder{}).Encode(&buf, 10, "3.1415926535"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if buf.String() != FormatPiOutput(10, "3.1415926535") {
+		t.Errorf("Expected TextEncoder output to match FormatPiOutput, got:\n%s", buf.String())
+	}
+}
+
+func TestJSONEncoder_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONEncoder{}).Encode(&buf, 5, "3.14159"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `"algorithm":"chudnovsky"`) {
+		t.Errorf("Expected algorithm field in output, got: %s", out)
+	}
+	if !strings.Contains(out, `"fraction":"14159"`) {
+		t.Errorf("Expected fraction digits in output, got: %s", out)
+	}
+}
+
+func TestBinaryEncoder_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (BinaryEncoder{}).Encode(&buf, 4, "3.1415"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data := buf.Bytes()
+	if len(data) < 12 {
+		t.Fatalf("Expected at least a 12-byte header, got %d bytes", len(data))
+	}
+	if magic := binary.BigEndian.Uint32(data[0:4]); magic != binaryMagic {
+		t.Errorf("Expected magic 0x%x, got 0x%x", binaryMagic, magic)
+	}
+	if count := binary.BigEndian.Uint64(data[4:12]); count != 4 {
+		t.Errorf("Expected digit count 4, got %d", count)
+	}
+	// "1415" packs into two BCD bytes: 0x14, 0x15.
+	if data[12] != 0x14 || data[13] != 0x15 {
+		t.Errorf("Expected packed BCD 0x14 0x15, got 0x%x 0x%x", data[12], data[13])
+	}
+}
+
+func TestPagedEncoder_WritesPagesAndManifest(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+
+	enc := PagedEncoder{Dir: dir, DigitsPerPage: 4}
+	if err := enc.Encode(&buf, 8, "3.12345678"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	page0, err := os.ReadFile(filepath.Join(dir, "page_0000.txt"))
+	if err != nil {
+		t.Fatalf("Expected page_0000.txt to exist: %v", err)
+	}
+	if string(page0) != "1234" {
+		t.Errorf("Expected first page to contain '1234', got %q", page0)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, pagedManifestName)); err != nil {
+		t.Errorf("Expected manifest file to exist: %v", err)
+	}
+}
+
+func TestYCruncherEncoder_WritesTextAndYCD(t *testing.T) {
+	ycdPath := filepath.Join(t.TempDir(), "pi.txt.ycd")
+	var buf bytes.Buffer
+
+	enc := YCruncherEncoder{LineWidth: 5, YCDPath: ycdPath}
+	if err := enc.Encode(&buf, 10, "3.1415926535"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "3.\n14159\n26535\n"
+	if buf.String() != want {
+		t.Errorf("Expected pi.txt content %q, got %q", want, buf.String())
+	}
+
+	data, err := os.ReadFile(ycdPath)
+	if err != nil {
+		t.Fatalf("Expected .ycd file to exist: %v", err)
+	}
+	if len(data) < 16 {
+		t.Fatalf("Expected at least a 16-byte header, got %d bytes", len(data))
+	}
+	if count := binary.LittleEndian.Uint64(data[0:8]); count != 10 {
+		t.Errorf("Expected digit count 10, got %d", count)
+	}
+	if exp := binary.LittleEndian.Uint64(data[8:16]); exp != ycdRadixExponent {
+		t.Errorf("Expected radix exponent %d, got %d", ycdRadixExponent, exp)
+	}
+
+	// Reconstruct the value from the little-endian base-10^19 limbs and
+	// confirm it round-trips to the original fraction digits.
+	radix := new(big.Int).Exp(big.NewInt(10), big.NewInt(ycdRadixExponent), nil)
+	value := new(big.Int)
+	for i := len(data) - 8; i >= 16; i -= 8 {
+		limb := new(big.Int).SetUint64(binary.LittleEndian.Uint64(data[i : i+8]))
+		value.Mul(value, radix)
+		value.Add(value, limb)
+	}
+	if value.String() != "1415926535" {
+		t.Errorf("Expected reconstructed value 1415926535, got %s", value.String())
+	}
+}
+
+func TestYCruncherEncoder_NoYCDPathSkipsBinaryFile(t *testing.T) {
+	var buf bytes.Buffer
+	enc := YCruncherEncoder{}
+	if err := enc.Encode(&buf, 4, "3.1415"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected pi.txt content to still be written")
+	}
+}
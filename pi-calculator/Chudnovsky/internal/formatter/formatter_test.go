@@ -51,3 +51,35 @@ func TestFormatPiOutput_EdgeCases(t *testing.T) {
 		t.Errorf("Expected at least 3 lines of 50 digits, got %d", digitLineCount)
 	}
 }
+
+// FuzzFormatPiOutput checks that FormatPiOutput never panics and always
+// includes a digit-count header, for any (digits, piStr) pair - including
+// digits/piStr combinations that don't actually agree with each other,
+// which a fuzzer will happily generate.
+func FuzzFormatPiOutput(f *testing.F) {
+	seeds := []struct {
+		digits int
+		piStr  string
+	}{
+		{10, "3.1415926535"},
+		{0, ""},
+		{1, "3"},
+		{1000, "3.14"},
+		{1000000, "3.14"},
+		{150, "3." + strings.Repeat("1", 150)},
+		{-1, "3.14159"},
+		{5, "3.1.4"},
+		{4, "\x00\x00\x00\x00"},
+		{5, "not a pi string"},
+	}
+	for _, s := range seeds {
+		f.Add(s.digits, s.piStr)
+	}
+
+	f.Fuzz(func(t *testing.T, digits int, piStr string) {
+		output := FormatPiOutput(digits, piStr)
+		if !strings.Contains(output, "Digits of Pi") {
+			t.Errorf("FormatPiOutput(%d, %q) = %q, expected a digit-count header", digits, piStr, output)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Package metrics exposes Prometheus counters, histograms, and gauges for
+// the Chudnovsky calculator and worker pool, so a long-running
+// billion-digit job can be observed in Grafana instead of by scraping
+// stdout log lines. Metrics are registered at package init time and are
+// safe for concurrent use from any number of worker goroutines.
+package metrics
+
+import (
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// TermsCompletedTotal counts Chudnovsky series terms computed across
+	// all ComputePQT calls. It is fed from calculator's progress signal
+	// via RecordProgress rather than incremented directly, since that
+	// signal already tracks the authoritative cumulative term count.
+	TermsCompletedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "chudnovsky_terms_completed_total",
+		Help: "Total number of Chudnovsky series terms computed.",
+	})
+
+	// BignumBitLength observes the bit length of P, Q, and T big.Int
+	// values as they're produced by ComputePQT and CombineResults, so an
+	// operator can see how quickly the binary-splitting tree's
+	// intermediate values grow.
+	BignumBitLength = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "chudnovsky_bignum_bit_length",
+		Help:    "Bit length of P/Q/T big.Int values produced during PQT computation.",
+		Buckets: prometheus.ExponentialBuckets(64, 2, 24), // 64 bits .. ~512M bits
+	})
+
+	// WorkerPoolQueueDepth is the current number of tasks queued but not
+	// yet dispatched to a worker goroutine in workerpool.Pool.
+	WorkerPoolQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "chudnovsky_workerpool_queue_depth",
+		Help: "Current number of tasks queued (not yet executing) in the worker pool.",
+	})
+
+	// ChunkDuration observes the wall-clock time a single worker pool
+	// task's computeFn takes to run.
+	ChunkDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "chudnovsky_chunk_duration_seconds",
+		Help:    "Wall-clock duration of a single worker pool task's computeFn.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// GCPauseSeconds observes individual stop-the-world GC pause
+	// durations, sampled from runtime.MemStats.
+	GCPauseSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "chudnovsky_gc_pause_seconds",
+		Help:    "Observed stop-the-world GC pause durations.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	// ProgressEventsDroppedTotal counts progress events a
+	// calculator.ChannelReporter discarded because its consumer wasn't
+	// draining Events() fast enough, so a stalled progress bar shows up
+	// here instead of silently under-reporting.
+	ProgressEventsDroppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "chudnovsky_progress_events_dropped_total",
+		Help: "Total number of progress events dropped by a ChannelReporter because its consumer fell behind.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		TermsCompletedTotal,
+		BignumBitLength,
+		WorkerPoolQueueDepth,
+		ChunkDuration,
+		GCPauseSeconds,
+		ProgressEventsDroppedTotal,
+	)
+}
+
+// lastTermsCompleted tracks the last cumulative value RecordProgress saw,
+// so it can report the incremental delta a Prometheus counter requires.
+var lastTermsCompleted int64
+
+// RecordProgress feeds a cumulative current-term-count signal into
+// TermsCompletedTotal. Callers should wire this alongside any other
+// consumer of a calculator.ProgressReporter (a progress bar, for instance)
+// rather than in place of it.
+func RecordProgress(current int64) {
+	previous := atomic.SwapInt64(&lastTermsCompleted, current)
+	if delta := current - previous; delta > 0 {
+		TermsCompletedTotal.Add(float64(delta))
+	}
+}
+
+// RecordDroppedProgressEvent increments ProgressEventsDroppedTotal. Called
+// from calculator.ChannelReporter.Report when its buffer is full.
+func RecordDroppedProgressEvent() {
+	ProgressEventsDroppedTotal.Inc()
+}
+
+// ObserveBigIntBitLengths records the bit length of P, Q, and T into
+// BignumBitLength. Nil values (e.g. an empty identity result) are skipped.
+func ObserveBigIntBitLengths(p, q, t *big.Int) {
+	for _, v := range []*big.Int{p, q, t} {
+		if v != nil {
+			BignumBitLength.Observe(float64(v.BitLen()))
+		}
+	}
+}
+
+// ObserveChunkDuration records how long a worker pool task's computeFn
+// took to run.
+func ObserveChunkDuration(d time.Duration) {
+	ChunkDuration.Observe(d.Seconds())
+}
+
+// IncQueueDepth and DecQueueDepth track WorkerPoolQueueDepth as tasks are
+// enqueued and subsequently handed off to a worker goroutine.
+func IncQueueDepth() { WorkerPoolQueueDepth.Inc() }
+func DecQueueDepth() { WorkerPoolQueueDepth.Dec() }
+
+// lastNumGC is the runtime.MemStats.NumGC value SampleGCPauses last saw,
+// so repeated calls only observe pauses that occurred since the last
+// sample instead of re-recording the same circular pause buffer entries.
+var lastNumGC uint32
+
+// memStatsReader abstracts runtime.ReadMemStats for testability.
+type memStatsReader func() (numGC uint32, recentPauseNs [256]uint64)
+
+// SampleGCPauses reads the new GC pauses (if any) since the last call and
+// records them into GCPauseSeconds. Server does not call it, so
+// GCPauseSeconds only receives samples when a caller invokes it.
+func SampleGCPauses(read memStatsReader) {
+	numGC, pauses := read()
+	last := atomic.SwapUint32(&lastNumGC, numGC)
+	if numGC <= last {
+		return
+	}
+
+	// runtime.MemStats.PauseNs is a circular buffer of the most recent
+	// 256 pause durations; don't replay more entries than actually
+	// occurred since the last sample.
+	newPauses := numGC - last
+	if newPauses > 256 {
+		newPauses = 256
+	}
+	for i := uint32(0); i < newPauses; i++ {
+		idx := (numGC - 1 - i) % 256
+		GCPauseSeconds.Observe(time.Duration(pauses[idx]).Seconds())
+	}
+}
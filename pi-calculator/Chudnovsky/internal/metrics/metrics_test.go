@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// histogramSampleCount returns the total number of observations recorded
+// by an unlabeled Histogram, since testutil.CollectAndCount reports the
+// number of metric series (always 1 here), not the number of Observe
+// calls that went into it.
+func histogramSampleCount(h prometheus.Histogram) uint64 {
+	m := &dto.Metric{}
+	_ = h.Write(m)
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestRecordProgress_AccumulatesDelta(t *testing.T) {
+	before := testutil.ToFloat64(TermsCompletedTotal)
+
+	RecordProgress(100)
+	RecordProgress(150)
+
+	after := testutil.ToFloat64(TermsCompletedTotal)
+	if got, want := after-before, 150.0; got != want {
+		t.Errorf("Expected TermsCompletedTotal to advance by %.0f, got %.0f", want, got)
+	}
+}
+
+func TestRecordProgress_IgnoresNonIncreasingValues(t *testing.T) {
+	RecordProgress(500)
+	before := testutil.ToFloat64(TermsCompletedTotal)
+
+	RecordProgress(500)
+	RecordProgress(200)
+
+	after := testutil.ToFloat64(TermsCompletedTotal)
+	if after != before {
+		t.Errorf("Expected no change for a non-increasing progress value, went from %.0f to %.0f", before, after)
+	}
+}
+
+func TestObserveBigIntBitLengths_SkipsNil(t *testing.T) {
+	// Must not panic when some values are nil (e.g. an identity result).
+	ObserveBigIntBitLengths(big.NewInt(255), nil, big.NewInt(1))
+}
+
+func TestSampleGCPauses_RecordsOnlyNewPauses(t *testing.T) {
+	var pauses [256]uint64
+	pauses[0] = uint64(2_000_000) // 2ms, most recent slot for numGC=1
+
+	before := histogramSampleCount(GCPauseSeconds)
+
+	SampleGCPauses(func() (uint32, [256]uint64) { return 1, pauses })
+	afterFirst := histogramSampleCount(GCPauseSeconds)
+	if afterFirst != before+1 {
+		t.Errorf("Expected exactly one new observation, count went from %d to %d", before, afterFirst)
+	}
+
+	// Calling again with the same numGC should not double-record.
+	SampleGCPauses(func() (uint32, [256]uint64) { return 1, pauses })
+	afterSecond := histogramSampleCount(GCPauseSeconds)
+	if afterSecond != afterFirst {
+		t.Errorf("Expected no new observation for an unchanged numGC, count went from %d to %d", afterFirst, afterSecond)
+	}
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PoolHealth reports whether a worker pool is still accepting and
+// completing work. workerpool.Pool satisfies this via its Alive method.
+type PoolHealth interface {
+	Alive() bool
+}
+
+// DeadlineCheck reports whether the current computation is still within
+// its deadline, if one was set. ok is true when there's no deadline in
+// effect, or the deadline hasn't passed yet; remaining is the time left
+// (meaningless when ok is false because the deadline already passed, or
+// when there is no deadline at all).
+type DeadlineCheck func() (ok bool, remaining time.Duration)
+
+// Server serves /metrics (Prometheus exposition format) and /healthz
+// (JSON, reporting worker pool liveness and deadline status) on a single
+// HTTP listener, so an operator can point Grafana/Prometheus and a
+// liveness probe at the same -metrics-addr.
+type Server struct {
+	httpServer *http.Server
+	pool       PoolHealth
+	deadline   DeadlineCheck
+}
+
+// NewServer creates a Server listening on addr. pool and deadline may
+// both be nil, in which case /healthz reports the pool as alive and the
+// computation as always within deadline.
+func NewServer(addr string, pool PoolHealth, deadline DeadlineCheck) *Server {
+	s := &Server{pool: pool, deadline: deadline}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	s.httpServer = &http.Server{Addr: addr, Handler: mux}
+
+	return s
+}
+
+// ListenAndServe starts serving and blocks until the server is closed or
+// fails to start. It always returns a non-nil error, per net/http.Server
+// convention (http.ErrServerClosed on a clean Close).
+func (s *Server) ListenAndServe() error {
+	return s.httpServer.ListenAndServe()
+}
+
+// Close shuts down the HTTP listener.
+func (s *Server) Close() error {
+	return s.httpServer.Close()
+}
+
+// handleHealthz reports 200 when the worker pool is alive and the
+// computation is within its deadline, 503 otherwise.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	alive := s.pool == nil || s.pool.Alive()
+
+	withinDeadline := true
+	var remaining time.Duration
+	if s.deadline != nil {
+		withinDeadline, remaining = s.deadline()
+	}
+
+	status := http.StatusOK
+	if !alive || !withinDeadline {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"pool_alive":%t,"within_deadline":%t,"deadline_remaining_seconds":%.3f}`,
+		alive, withinDeadline, remaining.Seconds())
+}
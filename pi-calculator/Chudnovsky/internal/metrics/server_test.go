@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakePoolHealth struct{ alive bool }
+
+func (f fakePoolHealth) Alive() bool { return f.alive }
+
+func TestServer_Healthz_OK(t *testing.T) {
+	s := NewServer(":0", fakePoolHealth{alive: true}, func() (bool, time.Duration) {
+		return true, time.Minute
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	s.handleHealthz(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestServer_Healthz_PoolDead(t *testing.T) {
+	s := NewServer(":0", fakePoolHealth{alive: false}, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	s.handleHealthz(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 for a dead pool, got %d", rec.Code)
+	}
+}
+
+func TestServer_Healthz_DeadlineExceeded(t *testing.T) {
+	s := NewServer(":0", nil, func() (bool, time.Duration) {
+		return false, 0
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	s.handleHealthz(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 for an exceeded deadline, got %d", rec.Code)
+	}
+}
+
+func TestServer_Healthz_NilDependenciesDefaultHealthy(t *testing.T) {
+	s := NewServer(":0", nil, nil)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	s.handleHealthz(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with no pool or deadline configured, got %d", rec.Code)
+	}
+}
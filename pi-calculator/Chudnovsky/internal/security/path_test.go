@@ -3,6 +3,7 @@ package security
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -49,3 +50,48 @@ func TestSanitizePath_EdgeCases(t *testing.T) {
 		t.Errorf("Expected normalized path to work: %v", err)
 	}
 }
+
+// FuzzSanitizePath checks SanitizePath's two core invariants hold for any
+// input: a path it accepts (err == nil) must never still contain a ".."
+// segment, and must never resolve outside os.Getwd().
+func FuzzSanitizePath(f *testing.F) {
+	seeds := []string{
+		"../../../etc/passwd",
+		"results/../test.txt",
+		"/etc/passwd",
+		"/root/.ssh/id_rsa",
+		"",
+		"results/test.txt",
+		"a\x00b",
+		"..\\..\\windows\\system32",
+		"./a/./b/../../c",
+		"symlink/../../../escape",
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, path string) {
+		result, err := SanitizePath(path)
+		if err != nil {
+			return
+		}
+
+		if strings.Contains(result, "..") {
+			t.Errorf("SanitizePath(%q) = %q, which still contains a \"..\" segment", path, result)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return
+		}
+		absPath, err := filepath.Abs(result)
+		if err != nil {
+			t.Fatalf("SanitizePath(%q) returned %q, which is not a valid path: %v", path, result, err)
+		}
+		relPath, err := filepath.Rel(cwd, absPath)
+		if err == nil && strings.HasPrefix(relPath, "..") {
+			t.Errorf("SanitizePath(%q) = %q, which escapes the working directory %q", path, result, cwd)
+		}
+	})
+}
@@ -0,0 +1,225 @@
+// Package verifier independently recomputes hexadecimal digits of π using
+// the Bailey-Borwein-Plouffe (BBP) spigot formula. Because BBP can extract
+// any hex digit of π without computing the digits before it, it gives a
+// cross-check for a Chudnovsky-derived result that is algorithmically
+// unrelated to the series summation and binary-splitting combine step, so
+// the two are unlikely to share the same bug.
+package verifier
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"runtime"
+)
+
+// tailEpsilon is the convergence threshold for the slowly-varying tail of
+// each BBP series; terms smaller than this no longer affect the resulting
+// hex digit at float64 precision.
+const tailEpsilon = 1e-17
+
+// cancelCheckInterval controls how often the k-loop checks ctx for
+// cancellation, to keep the check cheap relative to the per-k work.
+const cancelCheckInterval = 4096
+
+// HexDigit returns the hexadecimal digit of π at 0-indexed position n after
+// the point (so n=0 is the first digit after "3."), computed via the BBP
+// formula independently of any decimal-digit computation.
+func HexDigit(ctx context.Context, n int64) (byte, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("position must be non-negative, got %d", n)
+	}
+
+	s1, err := series(ctx, n, 1)
+	if err != nil {
+		return 0, err
+	}
+	s4, err := series(ctx, n, 4)
+	if err != nil {
+		return 0, err
+	}
+	s5, err := series(ctx, n, 5)
+	if err != nil {
+		return 0, err
+	}
+	s6, err := series(ctx, n, 6)
+	if err != nil {
+		return 0, err
+	}
+
+	x := 4*s1 - 2*s4 - s5 - s6
+	frac := x - math.Floor(x)
+	digit := byte(math.Floor(frac * 16))
+	return digit, nil
+}
+
+// series computes S(j,n) = sum_{k=0..n} (16^(n-k) mod (8k+j))/(8k+j) +
+// sum_{k=n+1..inf} 16^(n-k)/(8k+j), fractional part only. The first sum
+// uses modular exponentiation so each term stays bounded regardless of n;
+// the second sum is a rapidly converging float64 tail.
+func series(ctx context.Context, n, j int64) (float64, error) {
+	var sum float64
+
+	for k := int64(0); k <= n; k++ {
+		if k%cancelCheckInterval == 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			default:
+			}
+		}
+
+		denom := 8*k + j
+		r := modPow16(n-k, denom)
+		sum += float64(r) / float64(denom)
+		sum -= math.Floor(sum)
+	}
+
+	term := 1.0 / 16.0
+	for k := n + 1; ; k++ {
+		denom := 8*k + j
+		t := term / float64(denom)
+		if t < tailEpsilon {
+			break
+		}
+		sum += t
+		term /= 16
+	}
+
+	return sum - math.Floor(sum), nil
+}
+
+// HexDigitParallel is equivalent to HexDigit, but evaluates the four BBP
+// series concurrently and splits each series' finite k-loop across
+// multiple goroutines. S(1,n), S(4,n), S(5,n), S(6,n) have no data
+// dependency on one another, and summing fractional parts modulo 1 is
+// associative, so a chunk's partial sum can be combined with the others
+// regardless of evaluation order. Intended for probing a single large n
+// (e.g. VerifyHexDigit); for the small windows Verify checks, the plain
+// sequential HexDigit is cheaper than the goroutine fan-out.
+func HexDigitParallel(ctx context.Context, n int64) (byte, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("position must be non-negative, got %d", n)
+	}
+
+	type seriesResult struct {
+		j     int64
+		value float64
+		err   error
+	}
+
+	js := []int64{1, 4, 5, 6}
+	resultsCh := make(chan seriesResult, len(js))
+	for _, j := range js {
+		go func(j int64) {
+			v, err := seriesParallel(ctx, n, j)
+			resultsCh <- seriesResult{j: j, value: v, err: err}
+		}(j)
+	}
+
+	values := make(map[int64]float64, len(js))
+	for range js {
+		r := <-resultsCh
+		if r.err != nil {
+			return 0, r.err
+		}
+		values[r.j] = r.value
+	}
+
+	x := 4*values[1] - 2*values[4] - values[5] - values[6]
+	frac := x - math.Floor(x)
+	return byte(math.Floor(frac * 16)), nil
+}
+
+// seriesParallel computes the same value as series, but splits the
+// k in [0,n] modular sum into contiguous chunks of roughly equal size,
+// one per available CPU, and sums each chunk's result concurrently.
+func seriesParallel(ctx context.Context, n, j int64) (float64, error) {
+	totalK := n + 1
+	workers := int64(runtime.NumCPU())
+	if workers > totalK {
+		workers = totalK
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	chunk := totalK / workers
+	if chunk < 1 {
+		chunk = 1
+	}
+
+	type chunkResult struct {
+		value float64
+		err   error
+	}
+
+	var ranges [][2]int64
+	for start := int64(0); start < totalK; start += chunk {
+		end := start + chunk - 1
+		if end >= totalK {
+			end = totalK - 1
+		}
+		ranges = append(ranges, [2]int64{start, end})
+	}
+
+	resultsCh := make(chan chunkResult, len(ranges))
+	for _, rg := range ranges {
+		go func(start, end int64) {
+			v, err := modularSumChunk(ctx, n, j, start, end)
+			resultsCh <- chunkResult{value: v, err: err}
+		}(rg[0], rg[1])
+	}
+
+	var sum float64
+	for range ranges {
+		r := <-resultsCh
+		if r.err != nil {
+			return 0, r.err
+		}
+		sum += r.value
+		sum -= math.Floor(sum)
+	}
+
+	term := 1.0 / 16.0
+	for k := n + 1; ; k++ {
+		denom := 8*k + j
+		t := term / float64(denom)
+		if t < tailEpsilon {
+			break
+		}
+		sum += t
+		term /= 16
+	}
+
+	return sum - math.Floor(sum), nil
+}
+
+// modularSumChunk computes sum_{k=start..end} (16^(n-k) mod (8k+j))/(8k+j),
+// reduced modulo 1 as it accumulates, for one chunk of seriesParallel's
+// k-range.
+func modularSumChunk(ctx context.Context, n, j, start, end int64) (float64, error) {
+	var sum float64
+	for k := start; k <= end; k++ {
+		if (k-start)%cancelCheckInterval == 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			default:
+			}
+		}
+		denom := 8*k + j
+		r := modPow16(n-k, denom)
+		sum += float64(r) / float64(denom)
+		sum -= math.Floor(sum)
+	}
+	return sum, nil
+}
+
+// modPow16 computes 16^exp mod mod using math/big's modular exponentiation
+// so intermediate values never overflow regardless of how large mod grows
+// for high hex-digit positions.
+func modPow16(exp, mod int64) int64 {
+	result := new(big.Int).Exp(big.NewInt(16), big.NewInt(exp), big.NewInt(mod))
+	return result.Int64()
+}
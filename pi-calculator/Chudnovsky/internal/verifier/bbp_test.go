@@ -0,0 +1,80 @@
+package verifier
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHexDigit_KnownValues(t *testing.T) {
+	// The hex expansion of pi is 3.243F6A8885A308D3... so position 0
+	// (first hex digit after the point) must be 0x2.
+	ctx := context.Background()
+
+	digit, err := HexDigit(ctx, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if digit != 0x2 {
+		t.Errorf("Expected hex digit 0x2 at position 0, got 0x%x", digit)
+	}
+
+	digit, err = HexDigit(ctx, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if digit != 0x4 {
+		t.Errorf("Expected hex digit 0x4 at position 1, got 0x%x", digit)
+	}
+}
+
+func TestHexDigit_InvalidPosition(t *testing.T) {
+	_, err := HexDigit(context.Background(), -1)
+	if err == nil {
+		t.Error("Expected error for negative position")
+	}
+}
+
+func TestHexDigit_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := HexDigit(ctx, 100000)
+	if err == nil {
+		t.Error("Expected error due to context cancellation")
+	}
+}
+
+func TestHexDigitParallel_MatchesSequential(t *testing.T) {
+	ctx := context.Background()
+
+	for _, n := range []int64{0, 1, 5, 50000} {
+		sequential, err := HexDigit(ctx, n)
+		if err != nil {
+			t.Fatalf("HexDigit(%d): unexpected error: %v", n, err)
+		}
+		parallel, err := HexDigitParallel(ctx, n)
+		if err != nil {
+			t.Fatalf("HexDigitParallel(%d): unexpected error: %v", n, err)
+		}
+		if sequential != parallel {
+			t.Errorf("position %d: sequential=0x%x parallel=0x%x", n, sequential, parallel)
+		}
+	}
+}
+
+func TestHexDigitParallel_InvalidPosition(t *testing.T) {
+	_, err := HexDigitParallel(context.Background(), -1)
+	if err == nil {
+		t.Error("Expected error for negative position")
+	}
+}
+
+func TestHexDigitParallel_ContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := HexDigitParallel(ctx, 100000)
+	if err == nil {
+		t.Error("Expected error due to context cancellation")
+	}
+}
@@ -3,33 +3,156 @@
 package workerpool
 
 import (
+	"container/heap"
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/metrics"
 )
 
-// Pool manages parallel computation using a worker pool pattern.
-// It distributes work across multiple goroutines for efficient CPU utilization.
-// The pool is thread-safe and supports graceful shutdown.
+// queueFactor sizes the default bounded queue relative to the worker count.
+// A deep binary-splitting recursion can otherwise enqueue millions of tiny
+// tasks, each holding a closure over its own set of pending big.Int
+// results; this caps the live working set to roughly workers*queueFactor
+// outstanding tasks instead of letting it grow unbounded. Override with
+// WithQueueSize.
+const queueFactor = 4
+
+// ErrPoolClosed is returned (via config.Result.Err) for tasks that were
+// still queued, but not yet started, when Close was called.
+var ErrPoolClosed = errors.New("workerpool: pool closed")
+
+// PanicHandler is invoked with the recovered value and the task's [start,
+// end) range when computeFn panics, so the panic can be logged before it's
+// turned into an error result. Set via WithPanicHandler; the default logs
+// through the standard "log" package.
+type PanicHandler func(recovered any, start, end int64)
+
+// task is a unit of work waiting in the priority queue. Higher level values
+// are drained first, so deeper recursion levels (which unblock a larger
+// pending combine tree) are preferred over shallow ones.
+type task struct {
+	level      int
+	seq        int64
+	start, end int64
+	computeFn  func(ctx context.Context, a, b int64) (config.Result, error)
+	resultChan chan config.Result
+}
+
+// taskHeap is a container/heap.Interface ordering tasks by (-level, seq):
+// higher level first, and FIFO among tasks at the same level.
+type taskHeap []*task
+
+func (h taskHeap) Len() int { return len(h) }
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].level != h[j].level {
+		return h[i].level > h[j].level
+	}
+	return h[i].seq < h[j].seq
+}
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *taskHeap) Push(x any)   { *h = append(*h, x.(*task)) }
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// Pool manages parallel computation using a bounded, priority-aware worker
+// pool. workers persistent goroutines pull tasks from a shared priority
+// queue rather than one being spawned per submission, so the number of
+// live goroutines (and the big.Int working set they hold) stays fixed
+// regardless of how many tasks a deep binary-splitting recursion enqueues.
+// Pool is thread-safe and supports graceful shutdown.
 type Pool struct {
-	workers    int
-	wg         sync.WaitGroup
-	ctx        context.Context
-	cancel     context.CancelFunc
+	workers  int
+	queueCap int
+
+	// queueSem bounds the number of tasks queued but not yet handed to a
+	// worker to queueCap (a slot is released when a task is popped, before
+	// it runs); Submit and SubmitPriority block once it is full.
+	queueSem chan struct{}
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	pq   taskHeap
+	seq  int64
+
+	running int32 // atomic: tasks currently executing, for Running()
+
+	panicHandler PanicHandler
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
 	closed     bool
 	closeMutex sync.Mutex
 }
 
-// New creates a new worker pool with the specified number of workers.
-// If workers is 0 or negative, it defaults to the number of CPU cores.
+// Option configures a Pool at construction time. See WithWorkers,
+// WithQueueSize, and WithPanicHandler.
+type Option func(*Pool)
+
+// WithWorkers overrides the number of persistent worker goroutines from
+// the value passed to New. n<=0 is ignored.
+func WithWorkers(n int) Option {
+	return func(p *Pool) {
+		if n > 0 {
+			p.workers = n
+		}
+	}
+}
+
+// WithQueueSize overrides the bounded queue capacity from its
+// workers*queueFactor default. n<=0 is ignored.
+func WithQueueSize(n int) Option {
+	return func(p *Pool) {
+		if n > 0 {
+			p.queueCap = n
+		}
+	}
+}
+
+// WithPanicHandler overrides the default PanicHandler. A nil handler is
+// ignored.
+func WithPanicHandler(h PanicHandler) Option {
+	return func(p *Pool) {
+		if h != nil {
+			p.panicHandler = h
+		}
+	}
+}
+
+// defaultPanicHandler logs a recovered panic through the standard "log"
+// package, keeping the workerpool package free of an opinion about the
+// caller's own logging setup (cmd/chudnovsky, for instance, uses slog).
+func defaultPanicHandler(recovered any, start, end int64) {
+	log.Printf("workerpool: recovered from panic in task [%d,%d): %v", start, end, recovered)
+}
+
+// New creates a new worker pool with the specified number of persistent
+// worker goroutines. If workers is 0 or negative, it defaults to the
+// number of CPU cores. The pending-task queue is bounded to
+// workers*queueFactor by default; Submit and SubmitPriority block once it
+// fills up. Pass opts to override the queue size, worker count, or panic
+// handling behavior.
 //
 // Parameters:
 //   - workers: Number of worker goroutines (0 = auto-detect from CPU count)
 //
 // Returns a new Pool instance ready to accept work.
-func New(workers int) *Pool {
+func New(workers int, opts ...Option) *Pool {
 	if workers <= 0 {
 		workers = runtime.NumCPU()
 		if workers < 1 {
@@ -39,69 +162,201 @@ func New(workers int) *Pool {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	wp := &Pool{
-		workers: workers,
-		ctx:     ctx,
-		cancel:  cancel,
+		workers:      workers,
+		queueCap:     workers * queueFactor,
+		ctx:          ctx,
+		cancel:       cancel,
+		panicHandler: defaultPanicHandler,
+	}
+	for _, opt := range opts {
+		opt(wp)
+	}
+	wp.cond = sync.NewCond(&wp.mu)
+	wp.queueSem = make(chan struct{}, wp.queueCap)
+
+	// Wake every worker blocked in nextTask's cond.Wait once the pool's
+	// context is cancelled, so Close doesn't have to wait for a task to be
+	// submitted before workers notice they should exit.
+	go func() {
+		<-ctx.Done()
+		wp.cond.Broadcast()
+	}()
+
+	wp.wg.Add(wp.workers)
+	for i := 0; i < wp.workers; i++ {
+		go wp.worker()
 	}
 
 	return wp
 }
 
-// Submit submits work to the pool and returns a channel to receive the result.
-// The computeFn will be executed in a goroutine to distribute work across cores.
-//
-// Parameters:
-//   - start: Start index (inclusive) for computation
-//   - end: End index (exclusive) for computation
-//   - computeFn: Function to execute for the given range
+// Submit submits work to the pool at the default priority level (0) and
+// returns a channel to receive the result. Equivalent to
+// SubmitPriority(0, start, end, computeFn).
+func (wp *Pool) Submit(start, end int64, computeFn func(ctx context.Context, a, b int64) (config.Result, error)) <-chan config.Result {
+	return wp.SubmitPriority(0, start, end, computeFn)
+}
+
+// SubmitPriority submits work at the given priority level and returns a
+// channel to receive the result. Higher level values are drained first,
+// so callers recursing into deeper binary-splitting levels should submit
+// with an increasing level to keep the pool draining depth-first rather
+// than breadth-first.
 //
-// Returns a channel that will receive the computation result.
-// The channel will be closed if the pool is closed.
-func (wp *Pool) Submit(start, end int64, computeFn func(a, b int64) config.Result) <-chan config.Result {
+// SubmitPriority blocks, respecting the pool's internal context, while the
+// queue is at capacity. computeFn receives the pool's internal context,
+// which is cancelled when Close is called.
+func (wp *Pool) SubmitPriority(level int, start, end int64, computeFn func(ctx context.Context, a, b int64) (config.Result, error)) <-chan config.Result {
 	resultChan := make(chan config.Result, 1)
 
-	// Check if pool is closed
 	wp.closeMutex.Lock()
 	closed := wp.closed
 	wp.closeMutex.Unlock()
-
 	if closed {
 		close(resultChan)
 		return resultChan
 	}
 
-	// Execute computation in a goroutine for parallelism
-	wp.wg.Add(1)
-	go func() {
-		defer wp.wg.Done()
-		select {
-		case <-wp.ctx.Done():
-			close(resultChan)
+	select {
+	case wp.queueSem <- struct{}{}:
+	case <-wp.ctx.Done():
+		resultChan <- config.Result{Err: wp.ctx.Err()}
+		return resultChan
+	}
+
+	wp.mu.Lock()
+	wp.seq++
+	heap.Push(&wp.pq, &task{
+		level:      level,
+		seq:        wp.seq,
+		start:      start,
+		end:        end,
+		computeFn:  computeFn,
+		resultChan: resultChan,
+	})
+	wp.mu.Unlock()
+	metrics.IncQueueDepth()
+	wp.cond.Broadcast()
+
+	return resultChan
+}
+
+// worker is the body of one of the pool's persistent goroutines: it pulls
+// the highest-priority pending task and runs it inline, looping until the
+// pool is closed and the queue has drained.
+func (wp *Pool) worker() {
+	defer wp.wg.Done()
+	for {
+		t, ok := wp.nextTask()
+		if !ok {
 			return
-		default:
-			result := computeFn(start, end)
-			resultChan <- result
+		}
+		wp.execute(t)
+	}
+}
+
+// nextTask blocks until a task is available or the pool's context is
+// cancelled with nothing left queued, in which case it returns ok=false so
+// the calling worker can exit.
+func (wp *Pool) nextTask() (t *task, ok bool) {
+	wp.mu.Lock()
+	for len(wp.pq) == 0 {
+		if wp.ctx.Err() != nil {
+			wp.mu.Unlock()
+			return nil, false
+		}
+		wp.cond.Wait()
+	}
+	t = heap.Pop(&wp.pq).(*task)
+	wp.mu.Unlock()
+	metrics.DecQueueDepth()
+
+	<-wp.queueSem
+	return t, true
+}
+
+// execute runs t's computeFn, recovering a panic into an error result via
+// the pool's PanicHandler rather than letting it crash the computation.
+// Tasks that were still queued when the pool's context was cancelled are
+// failed with ErrPoolClosed without ever being dispatched to computeFn.
+func (wp *Pool) execute(t *task) {
+	if wp.ctx.Err() != nil {
+		t.resultChan <- config.Result{Err: ErrPoolClosed}
+		return
+	}
+
+	atomic.AddInt32(&wp.running, 1)
+	defer atomic.AddInt32(&wp.running, -1)
+
+	result, err := wp.runComputeFn(t)
+	if err != nil {
+		result.Err = err
+	}
+	t.resultChan <- result
+}
+
+// runComputeFn invokes t.computeFn, recovering and reporting any panic as
+// an error instead of propagating it up through the worker goroutine.
+func (wp *Pool) runComputeFn(t *task) (result config.Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			wp.panicHandler(r, t.start, t.end)
+			err = fmt.Errorf("workerpool: task [%d,%d) panicked: %v", t.start, t.end, r)
 		}
 	}()
 
-	return resultChan
+	chunkStart := time.Now()
+	result, err = t.computeFn(wp.ctx, t.start, t.end)
+	metrics.ObserveChunkDuration(time.Since(chunkStart))
+	return result, err
 }
 
-// Close shuts down the worker pool (idempotent).
-// It signals all workers to stop and waits for them to finish.
-// Safe to call multiple times.
-func (wp *Pool) Close() {
+// Running reports how many tasks are currently executing.
+func (wp *Pool) Running() int {
+	return int(atomic.LoadInt32(&wp.running))
+}
+
+// Waiting reports how many tasks are queued but not yet dispatched to a
+// worker.
+func (wp *Pool) Waiting() int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return len(wp.pq)
+}
+
+// Cap reports the bounded queue's capacity.
+func (wp *Pool) Cap() int {
+	return wp.queueCap
+}
+
+// Alive reports whether the pool is still accepting and dispatching work.
+// It implements metrics.PoolHealth, so the /healthz endpoint can report
+// pool liveness.
+func (wp *Pool) Alive() bool {
 	if wp == nil {
-		return
+		return true
 	}
 	wp.closeMutex.Lock()
 	defer wp.closeMutex.Unlock()
+	return !wp.closed
+}
 
+// Close shuts down the worker pool (idempotent). It cancels the pool's
+// internal context so in-flight computeFn calls can unwind promptly,
+// fails any task still queued but not yet started with ErrPoolClosed, and
+// waits for all workers to exit. Safe to call multiple times.
+func (wp *Pool) Close() {
+	if wp == nil {
+		return
+	}
+	wp.closeMutex.Lock()
 	if wp.closed {
-		return // Already closed
+		wp.closeMutex.Unlock()
+		return
 	}
 	wp.closed = true
+	wp.closeMutex.Unlock()
 
-	wp.cancel()  // Signal workers to stop first
-	wp.wg.Wait() // Wait for all workers to finish
+	wp.cancel()
+	wp.wg.Wait()
 }
@@ -1,7 +1,10 @@
 package workerpool
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
 )
@@ -18,8 +21,8 @@ func TestPoolSubmit_Package(t *testing.T) {
 	wp := New(2)
 	defer wp.Close()
 
-	resultChan := wp.Submit(0, 1, func(a, b int64) config.Result {
-		return config.Result{P: nil, Q: nil, T: nil} // Placeholder
+	resultChan := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		return config.Result{P: nil, Q: nil, T: nil}, nil // Placeholder
 	})
 
 	<-resultChan
@@ -37,8 +40,8 @@ func TestPool_SubmitAfterClose(t *testing.T) {
 	wp.Close()
 
 	// Submit should handle closed pool gracefully
-	resultChan := wp.Submit(0, 1, func(a, b int64) config.Result {
-		return config.Result{P: nil, Q: nil, T: nil}
+	resultChan := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		return config.Result{P: nil, Q: nil, T: nil}, nil
 	})
 
 	_, ok := <-resultChan
@@ -55,3 +58,183 @@ func TestPool_AutoDetectWorkers(t *testing.T) {
 	}
 	wp.Close()
 }
+
+func TestPool_CloseUnwindsRunningWork(t *testing.T) {
+	wp := New(4)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	resultChan := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return config.Result{}, ctx.Err()
+		case <-release:
+			return config.Result{P: nil, Q: nil, T: nil}, nil
+		}
+	})
+
+	<-started
+	wp.Close()
+
+	result := <-resultChan
+	if result.Err == nil {
+		t.Error("Expected Close to cancel the in-flight computation")
+	}
+	close(release)
+}
+
+func TestPool_SubmitBlocksWhenQueueFull(t *testing.T) {
+	wp := New(1)
+	defer wp.Close()
+
+	release := make(chan struct{})
+	// Occupy the single worker so nothing drains the queue.
+	wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		<-release
+		return config.Result{}, nil
+	})
+
+	// Fill the bounded queue (workers * queueFactor = 1 * 4).
+	for i := 0; i < wp.queueCap; i++ {
+		wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+			<-release
+			return config.Result{}, nil
+		})
+	}
+
+	submitted := make(chan struct{})
+	go func() {
+		wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+			<-release
+			return config.Result{}, nil
+		})
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Error("Expected Submit to block once the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-submitted:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected blocked Submit to unblock once the queue drained")
+	}
+}
+
+func TestPool_WithQueueSizeAndWorkers(t *testing.T) {
+	wp := New(1, WithWorkers(3), WithQueueSize(10))
+	defer wp.Close()
+
+	if wp.workers != 3 {
+		t.Errorf("Expected WithWorkers to override worker count to 3, got %d", wp.workers)
+	}
+	if wp.Cap() != 10 {
+		t.Errorf("Expected WithQueueSize to set capacity to 10, got %d", wp.Cap())
+	}
+}
+
+func TestPool_RunningAndWaiting(t *testing.T) {
+	wp := New(1)
+	defer wp.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		close(started)
+		<-release
+		return config.Result{}, nil
+	})
+	<-started
+
+	wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		return config.Result{}, nil
+	})
+
+	if got := wp.Running(); got != 1 {
+		t.Errorf("Expected 1 running task, got %d", got)
+	}
+	if got := wp.Waiting(); got != 1 {
+		t.Errorf("Expected 1 waiting task, got %d", got)
+	}
+	close(release)
+}
+
+func TestPool_PanicInComputeFnIsRecovered(t *testing.T) {
+	var handled bool
+	var mu sync.Mutex
+	wp := New(2, WithPanicHandler(func(recovered any, start, end int64) {
+		mu.Lock()
+		handled = true
+		mu.Unlock()
+	}))
+	defer wp.Close()
+
+	resultChan := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		panic("boom")
+	})
+
+	result := <-resultChan
+	if result.Err == nil {
+		t.Error("Expected a panic to be reported as an error result")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !handled {
+		t.Error("Expected the custom PanicHandler to be invoked")
+	}
+}
+
+func TestPool_SubmitPriorityDrainsDepthFirst(t *testing.T) {
+	wp := New(1)
+	defer wp.Close()
+
+	release := make(chan struct{})
+	// Occupy the single worker so every subsequent submission queues up
+	// and dispatch order is driven entirely by priority.
+	wp.SubmitPriority(0, 0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		<-release
+		return config.Result{}, nil
+	})
+
+	var mu sync.Mutex
+	var order []int64
+
+	record := func(level int) func(ctx context.Context, a, b int64) (config.Result, error) {
+		return func(ctx context.Context, a, b int64) (config.Result, error) {
+			mu.Lock()
+			order = append(order, int64(level))
+			mu.Unlock()
+			return config.Result{}, nil
+		}
+	}
+
+	done := make([]<-chan config.Result, 0, 3)
+	done = append(done, wp.SubmitPriority(1, 0, 1, record(1)))
+	done = append(done, wp.SubmitPriority(3, 0, 1, record(3)))
+	done = append(done, wp.SubmitPriority(2, 0, 1, record(2)))
+
+	close(release)
+	for _, ch := range done {
+		<-ch
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []int64{3, 2, 1}
+	if len(order) != len(want) {
+		t.Fatalf("Expected %d dispatched tasks, got %d", len(want), len(order))
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("Expected dispatch order %v, got %v", want, order)
+			break
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package workerpool
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/metrics"
+)
+
+// stealTask is a unit of work living in a worker's deque. Unlike Pool's
+// task, it carries the submitting call's own context (rather than just the
+// pool's), since SubmitRecursive is called from deep inside a recursive
+// ComputePQT and that context may carry a checkpoint store or deadline.
+type stealTask struct {
+	ctx        context.Context
+	start, end int64
+	computeFn  func(ctx context.Context, a, b int64) (config.Result, error)
+	resultChan chan config.Result
+}
+
+// dequeue is a double-ended queue of pending stealTasks. The owning worker
+// pushes and pops its own bottom (LIFO, for cache locality with the
+// recursive split that produced the tasks); other workers steal from the
+// top (the oldest pending split, so a steal grabs a large chunk of
+// still-undivided work rather than racing the owner for its most recent,
+// likely-smallest one). A mutex guards the shared slice in place of a
+// lock-free CAS ring buffer: splits happen far less often than individual
+// terms, so the extra safety is worth far more than the contention it
+// costs here.
+type dequeue struct {
+	mu    sync.Mutex
+	tasks []*stealTask
+}
+
+func (d *dequeue) pushBottom(t *stealTask) {
+	d.mu.Lock()
+	d.tasks = append(d.tasks, t)
+	d.mu.Unlock()
+}
+
+func (d *dequeue) popBottom() (*stealTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := len(d.tasks)
+	if n == 0 {
+		return nil, false
+	}
+	t := d.tasks[n-1]
+	d.tasks = d.tasks[:n-1]
+	return t, true
+}
+
+func (d *dequeue) popTop() (*stealTask, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.tasks) == 0 {
+		return nil, false
+	}
+	t := d.tasks[0]
+	d.tasks = d.tasks[1:]
+	return t, true
+}
+
+// stealWorkerContextKey is an unexported type so the value stored by
+// withStealWorker cannot collide with keys from other packages.
+type stealWorkerContextKey struct{}
+
+// withStealWorker returns a copy of ctx identifying w as the worker
+// currently executing on it, so a SubmitRecursive call made from inside a
+// running task pushes onto w's own deque instead of a round-robin victim.
+func withStealWorker(ctx context.Context, w *stealWorker) context.Context {
+	return context.WithValue(ctx, stealWorkerContextKey{}, w)
+}
+
+func stealWorkerFromContext(ctx context.Context) *stealWorker {
+	w, _ := ctx.Value(stealWorkerContextKey{}).(*stealWorker)
+	return w
+}
+
+// stealWorker is one of StealPool's persistent goroutines, each owning a
+// single deque.
+type stealWorker struct {
+	id    int
+	pool  *StealPool
+	deque dequeue
+}
+
+// StealOption configures a StealPool at construction time.
+type StealOption func(*StealPool)
+
+// WithStealPanicHandler overrides the default PanicHandler. A nil handler
+// is ignored.
+func WithStealPanicHandler(h PanicHandler) StealOption {
+	return func(sp *StealPool) {
+		if h != nil {
+			sp.panicHandler = h
+		}
+	}
+}
+
+// StealPool is a work-stealing scheduler, the counterpart to Pool's single
+// shared priority queue. Rather than flattening a range into a fixed
+// number of independent chunks up front, StealPool expects callers to
+// recurse: SubmitRecursive pushes one half of a split onto the calling
+// worker's own deque so the other half can be computed locally, and an
+// idle worker steals from a random victim's deque when its own is empty.
+// This keeps every core busy even when one subtree recurses much deeper
+// than its siblings, which a fixed chunk count can't adapt to.
+type StealPool struct {
+	workers []*stealWorker
+	next    int32 // atomic round-robin counter for externally-submitted work
+
+	panicHandler PanicHandler
+
+	// mu/cond signal idle workers when new work is pushed anywhere in the
+	// pool, so they don't have to busy-poll their own and every victim's
+	// deque.
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closed     bool
+	closeMutex sync.Mutex
+}
+
+// NewStealPool creates a work-stealing pool with the given number of
+// persistent worker goroutines. If workers is 0 or negative, it defaults
+// to the number of CPU cores.
+func NewStealPool(workers int, opts ...StealOption) *StealPool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+		if workers < 1 {
+			workers = 1
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sp := &StealPool{
+		ctx:          ctx,
+		cancel:       cancel,
+		panicHandler: defaultPanicHandler,
+	}
+	for _, opt := range opts {
+		opt(sp)
+	}
+	sp.cond = sync.NewCond(&sp.mu)
+
+	sp.workers = make([]*stealWorker, workers)
+	for i := range sp.workers {
+		sp.workers[i] = &stealWorker{id: i, pool: sp}
+	}
+
+	go func() {
+		<-ctx.Done()
+		sp.cond.Broadcast()
+	}()
+
+	sp.wg.Add(len(sp.workers))
+	for _, w := range sp.workers {
+		go w.run()
+	}
+
+	return sp
+}
+
+// Submit implements calculator.PoolInterface by delegating to
+// SubmitRecursive with the pool's own context, for callers that don't need
+// (or aren't positioned to provide) the recursive-split behavior.
+func (sp *StealPool) Submit(start, end int64, computeFn func(ctx context.Context, a, b int64) (config.Result, error)) <-chan config.Result {
+	return sp.SubmitRecursive(sp.ctx, start, end, computeFn)
+}
+
+// SubmitRecursive submits [start,end) for execution and returns a channel
+// to receive the result. If ctx identifies the StealPool worker currently
+// executing the calling goroutine (i.e. this is being called from inside a
+// task that StealPool itself dispatched), the new task is pushed onto that
+// worker's own deque; otherwise (the initial, externally-submitted call)
+// it's spread round-robin across workers so idle ones have something to
+// steal immediately.
+func (sp *StealPool) SubmitRecursive(ctx context.Context, start, end int64, computeFn func(ctx context.Context, a, b int64) (config.Result, error)) <-chan config.Result {
+	resultChan := make(chan config.Result, 1)
+
+	sp.closeMutex.Lock()
+	closed := sp.closed
+	sp.closeMutex.Unlock()
+	if closed {
+		close(resultChan)
+		return resultChan
+	}
+
+	t := &stealTask{ctx: ctx, start: start, end: end, computeFn: computeFn, resultChan: resultChan}
+
+	if w := stealWorkerFromContext(ctx); w != nil {
+		w.deque.pushBottom(t)
+	} else {
+		idx := int(atomic.AddInt32(&sp.next, 1)) % len(sp.workers)
+		sp.workers[idx].deque.pushBottom(t)
+	}
+
+	sp.mu.Lock()
+	sp.cond.Broadcast()
+	sp.mu.Unlock()
+
+	return resultChan
+}
+
+// run is the body of one of the pool's persistent goroutines: pop its own
+// deque, fall back to stealing from a random victim, and otherwise wait
+// to be woken by the next push anywhere in the pool.
+func (w *stealWorker) run() {
+	defer w.pool.wg.Done()
+	for {
+		t, ok := w.deque.popBottom()
+		if !ok {
+			t, ok = w.steal()
+		}
+		if ok {
+			w.execute(t)
+			continue
+		}
+
+		if w.pool.ctx.Err() != nil {
+			return
+		}
+
+		w.pool.mu.Lock()
+		w.pool.cond.Wait()
+		w.pool.mu.Unlock()
+	}
+}
+
+// steal tries every other worker's deque, in a random order, popping from
+// the top (the oldest pending task) of the first non-empty one found. It
+// checks for cancellation between each attempt rather than only before or
+// after the whole sweep.
+func (w *stealWorker) steal() (*stealTask, bool) {
+	n := len(w.pool.workers)
+	if n <= 1 {
+		return nil, false
+	}
+
+	start := rand.Intn(n) //nolint:gosec // scheduling jitter, not security-sensitive
+	for i := 0; i < n; i++ {
+		if w.pool.ctx.Err() != nil {
+			return nil, false
+		}
+		victim := w.pool.workers[(start+i)%n]
+		if victim == w {
+			continue
+		}
+		if t, ok := victim.deque.popTop(); ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// execute runs t's computeFn, recovering a panic via the pool's
+// PanicHandler. A task popped after the pool was closed is failed with
+// ErrPoolClosed without ever reaching computeFn.
+func (w *stealWorker) execute(t *stealTask) {
+	if w.pool.ctx.Err() != nil || t.ctx.Err() != nil {
+		t.resultChan <- config.Result{Err: ErrPoolClosed}
+		return
+	}
+
+	result, err := w.runComputeFn(t)
+	if err != nil {
+		result.Err = err
+	}
+	t.resultChan <- result
+}
+
+// runComputeFn invokes t.computeFn with t's own context (tagged with w so
+// a nested SubmitRecursive lands back on this worker's deque), recovering
+// and reporting any panic as an error instead of propagating it up through
+// the worker goroutine.
+func (w *stealWorker) runComputeFn(t *stealTask) (result config.Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.pool.panicHandler(r, t.start, t.end)
+			err = fmt.Errorf("workerpool: steal task [%d,%d) panicked: %v", t.start, t.end, r)
+		}
+	}()
+
+	ctx := withStealWorker(t.ctx, w)
+	chunkStart := time.Now()
+	result, err = t.computeFn(ctx, t.start, t.end)
+	metrics.ObserveChunkDuration(time.Since(chunkStart))
+	return result, err
+}
+
+// Alive reports whether the pool is still accepting and dispatching work.
+// It implements metrics.PoolHealth, so the /healthz endpoint can report
+// pool liveness.
+func (sp *StealPool) Alive() bool {
+	if sp == nil {
+		return true
+	}
+	sp.closeMutex.Lock()
+	defer sp.closeMutex.Unlock()
+	return !sp.closed
+}
+
+// Close shuts down the pool (idempotent). It cancels the pool's internal
+// context so in-flight computeFn calls can unwind promptly, wakes every
+// worker blocked waiting for work so queued-but-not-started tasks drain
+// and fail with ErrPoolClosed, and waits for all workers to exit. Safe to
+// call multiple times.
+func (sp *StealPool) Close() {
+	if sp == nil {
+		return
+	}
+	sp.closeMutex.Lock()
+	if sp.closed {
+		sp.closeMutex.Unlock()
+		return
+	}
+	sp.closed = true
+	sp.closeMutex.Unlock()
+
+	sp.cancel()
+	sp.wg.Wait()
+}
@@ -0,0 +1,165 @@
+package workerpool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+func TestStealPool_Package(t *testing.T) {
+	sp := NewStealPool(2)
+	if sp == nil {
+		t.Fatal("Expected non-nil pool")
+	}
+	sp.Close()
+}
+
+func TestStealPoolSubmit_Package(t *testing.T) {
+	sp := NewStealPool(2)
+	defer sp.Close()
+
+	resultChan := sp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		return config.Result{}, nil
+	})
+
+	<-resultChan
+}
+
+func TestStealPool_CloseIdempotent(t *testing.T) {
+	sp := NewStealPool(2)
+	sp.Close()
+	// Should not panic
+	sp.Close()
+}
+
+func TestStealPool_SubmitAfterClose(t *testing.T) {
+	sp := NewStealPool(2)
+	sp.Close()
+
+	resultChan := sp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		return config.Result{}, nil
+	})
+
+	_, ok := <-resultChan
+	if ok {
+		t.Error("Expected closed channel after pool close")
+	}
+}
+
+func TestStealPool_SubmitRecursivePushesToOwnWorker(t *testing.T) {
+	sp := NewStealPool(1)
+	defer sp.Close()
+
+	var nestedResult <-chan config.Result
+	outer := sp.Submit(0, 4, func(ctx context.Context, a, b int64) (config.Result, error) {
+		nestedResult = sp.SubmitRecursive(ctx, 2, 4, func(ctx context.Context, a, b int64) (config.Result, error) {
+			return config.Result{P: nil}, nil
+		})
+		return config.Result{}, nil
+	})
+
+	<-outer
+	select {
+	case <-nestedResult:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the recursively submitted task to complete")
+	}
+}
+
+func TestStealPool_StealsFromBusyWorker(t *testing.T) {
+	sp := NewStealPool(2)
+	defer sp.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	// Occupy one worker so it can't drain its own deque, then push work
+	// directly onto that same (now-busy) worker's deque - exactly what
+	// happens when a recursive split submits its other half onto the
+	// current worker. The only way it can run is if the idle worker
+	// steals it.
+	//
+	// Which worker actually ends up occupied is itself a race between the
+	// owner's popBottom and the other worker's steal()-driven popTop on
+	// this first, just-pushed task, so firstRanOn (not sp.workers[0]'s id)
+	// is what the second task's steal is checked against below.
+	busy := sp.workers[0]
+	var firstRanOn int
+	var mu sync.Mutex
+	busy.deque.pushBottom(&stealTask{
+		ctx: sp.ctx, start: 0, end: 1,
+		computeFn: func(ctx context.Context, a, b int64) (config.Result, error) {
+			w := stealWorkerFromContext(ctx)
+			mu.Lock()
+			firstRanOn = w.id
+			mu.Unlock()
+			close(started)
+			<-release
+			return config.Result{}, nil
+		},
+		resultChan: make(chan config.Result, 1),
+	})
+	sp.mu.Lock()
+	sp.cond.Broadcast()
+	sp.mu.Unlock()
+	<-started
+
+	var ranOn int
+	stolenResult := make(chan config.Result, 1)
+	busy.deque.pushBottom(&stealTask{
+		ctx: sp.ctx, start: 1, end: 2,
+		computeFn: func(ctx context.Context, a, b int64) (config.Result, error) {
+			w := stealWorkerFromContext(ctx)
+			mu.Lock()
+			ranOn = w.id
+			mu.Unlock()
+			return config.Result{}, nil
+		},
+		resultChan: stolenResult,
+	})
+	sp.mu.Lock()
+	sp.cond.Broadcast()
+	sp.mu.Unlock()
+
+	select {
+	case <-stolenResult:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the idle worker to steal and complete the queued task")
+	}
+	close(release)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if ranOn == firstRanOn {
+		t.Errorf("Expected the task to be stolen by a different worker than %d, ran on %d", firstRanOn, ranOn)
+	}
+}
+
+func TestStealPool_PanicInComputeFnIsRecovered(t *testing.T) {
+	var handled bool
+	var mu sync.Mutex
+	sp := NewStealPool(1, WithStealPanicHandler(func(recovered any, start, end int64) {
+		mu.Lock()
+		handled = true
+		mu.Unlock()
+	}))
+	defer sp.Close()
+
+	resultChan := sp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
+		panic("boom")
+	})
+
+	result := <-resultChan
+	if result.Err == nil {
+		t.Error("Expected a panic to be reported as an error result")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !handled {
+		t.Error("Expected the custom PanicHandler to be invoked")
+	}
+}
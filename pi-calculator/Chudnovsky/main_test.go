@@ -359,9 +359,9 @@ func TestWorkerPool(t *testing.T) {
 		defer wp.Close()
 
 		// Submit a small computation
-		resultChan := wp.Submit(0, 1, func(a, b int64) config.Result {
+		resultChan := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
 			p, q, t := calculator.ComputePQTSequential(a, b)
-			return config.Result{P: p, Q: q, T: t}
+			return config.Result{P: p, Q: q, T: t}, nil
 		})
 		result := <-resultChan
 
@@ -378,13 +378,13 @@ func TestWorkerPool(t *testing.T) {
 		defer wp.Close()
 
 		// Submit multiple work items
-		ch1 := wp.Submit(0, 1, func(a, b int64) config.Result {
+		ch1 := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
 			p, q, t := calculator.ComputePQTSequential(a, b)
-			return config.Result{P: p, Q: q, T: t}
+			return config.Result{P: p, Q: q, T: t}, nil
 		})
-		ch2 := wp.Submit(1, 2, func(a, b int64) config.Result {
+		ch2 := wp.Submit(1, 2, func(ctx context.Context, a, b int64) (config.Result, error) {
 			p, q, t := calculator.ComputePQTSequential(a, b)
-			return config.Result{P: p, Q: q, T: t}
+			return config.Result{P: p, Q: q, T: t}, nil
 		})
 
 		r1 := <-ch1
@@ -583,9 +583,9 @@ func TestWorkerPoolEdgeCases(t *testing.T) {
 				t.Errorf("Submit() panicked after close: %v", r)
 			}
 		}()
-		resultChan := wp.Submit(0, 1, func(a, b int64) config.Result {
+		resultChan := wp.Submit(0, 1, func(ctx context.Context, a, b int64) (config.Result, error) {
 			p, q, t := calculator.ComputePQTSequential(a, b)
-			return config.Result{P: p, Q: q, T: t}
+			return config.Result{P: p, Q: q, T: t}, nil
 		})
 		// Channel should be closed
 		_, ok := <-resultChan
@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/config"
+)
+
+// progressPollInterval is how often Coordinator asks a busy worker for its
+// current progress while a ComputeRange call is in flight.
+const progressPollInterval = 500 * time.Millisecond
+
+// ProgressFunc is called with a worker's cumulative progress count while
+// one of its ranges is in flight. It's a plain callback rather than a
+// calculator.ProgressReporter since Coordinator's progress signal comes
+// from polling a remote worker's Progress RPC, not from local
+// calculator.Event production.
+type ProgressFunc func(current int64)
+
+// Coordinator dispatches Chudnovsky PQT term ranges to a fixed set of
+// worker addresses over mutually-authenticated TLS, implementing
+// calculator.PoolInterface so it can be substituted for the in-process
+// workerpool.Pool in calculator.ComputePQT via the -workers flag.
+type Coordinator struct {
+	addrs      []string
+	tlsConfig  *tls.Config
+	onProgress ProgressFunc
+
+	mu      sync.Mutex
+	clients map[string]*rpc.Client
+}
+
+// NewCoordinator creates a Coordinator dispatching across addrs.
+// tlsConfig should carry the coordinator's client certificate and the
+// workers' CA pool for mutual authentication. onProgress, if non-nil, is
+// called with a worker's cumulative progress while its range is in
+// flight.
+func NewCoordinator(addrs []string, tlsConfig *tls.Config, onProgress ProgressFunc) *Coordinator {
+	return &Coordinator{
+		addrs:      addrs,
+		tlsConfig:  tlsConfig,
+		onProgress: onProgress,
+		clients:    make(map[string]*rpc.Client),
+	}
+}
+
+// Submit implements calculator.PoolInterface. computeFn is ignored: the
+// remote worker runs calculator.ComputePQTSequentialCtx itself rather than
+// the caller's closure, since that closure's checkpoint side effects only
+// make sense running locally. Submit instead dispatches [start, end) to
+// the worker WorkerForRange selects, retrying on the remaining workers if
+// it is unreachable or returns an error.
+func (c *Coordinator) Submit(start, end int64, _ func(ctx context.Context, a, b int64) (config.Result, error)) <-chan config.Result {
+	resultChan := make(chan config.Result, 1)
+	go func() {
+		resultChan <- c.dispatch(start, end)
+	}()
+	return resultChan
+}
+
+// dispatch tries WorkerForRange's pick first, then falls back through the
+// remaining workers in order so a single dead worker doesn't fail a range
+// outright.
+func (c *Coordinator) dispatch(start, end int64) config.Result {
+	order := orderedFrom(c.addrs, WorkerForRange(c.addrs, start, end))
+
+	var lastErr error
+	for _, addr := range order {
+		client, err := c.clientFor(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		stopPoll := c.pollProgress(client)
+		var reply ComputeRangeReply
+		err = client.Call("WorkerService.ComputeRange", ComputeRangeArgs{Start: start, End: end}, &reply)
+		stopPoll()
+
+		if err != nil {
+			c.evict(addr)
+			lastErr = fmt.Errorf("worker %s: %w", addr, err)
+			continue
+		}
+
+		return config.Result{P: reply.P.Decode(), Q: reply.Q.Decode(), T: reply.T.Decode()}
+	}
+
+	return config.Result{Err: fmt.Errorf("rpc: range [%d,%d) failed on all %d worker(s), last error: %w", start, end, len(c.addrs), lastErr)}
+}
+
+// pollProgress periodically asks client for its current progress and
+// forwards it to onProgress until the returned stop func is called.
+func (c *Coordinator) pollProgress(client *rpc.Client) (stop func()) {
+	if c.onProgress == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(progressPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				var reply ProgressReply
+				if err := client.Call("WorkerService.Progress", ProgressArgs{}, &reply); err == nil {
+					c.onProgress(reply.Current)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// clientFor returns a cached, or newly dialed, TLS-authenticated RPC
+// client for addr.
+func (c *Coordinator) clientFor(addr string) (*rpc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[addr]; ok {
+		return client, nil
+	}
+
+	conn, err := tls.Dial("tcp", addr, c.tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	client := rpc.NewClient(conn)
+	c.clients[addr] = client
+	return client, nil
+}
+
+// evict closes and forgets a worker's cached client, so the next dispatch
+// to that address redials rather than reusing a connection that just
+// failed.
+func (c *Coordinator) evict(addr string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[addr]; ok {
+		_ = client.Close()
+		delete(c.clients, addr)
+	}
+}
+
+// Alive reports whether at least one worker currently answers a health
+// check, so Coordinator satisfies internal/metrics.PoolHealth.
+func (c *Coordinator) Alive() bool {
+	for _, addr := range c.addrs {
+		client, err := c.clientFor(addr)
+		if err != nil {
+			continue
+		}
+		var reply HealthReply
+		if err := client.Call("WorkerService.HealthCheck", HealthArgs{}, &reply); err == nil && reply.Alive {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes every open worker connection. Safe to call once all
+// outstanding Submit results have been collected.
+func (c *Coordinator) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var firstErr error
+	for addr, client := range c.clients {
+		if err := client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.clients, addr)
+	}
+	return firstErr
+}
+
+// orderedFrom returns addrs rotated so that first appears at index 0,
+// preserving the relative order of the rest, so dispatch's retry loop
+// tries the deterministically-assigned worker before falling back to
+// others.
+func orderedFrom(addrs []string, first string) []string {
+	ordered := make([]string, 0, len(addrs))
+	ordered = append(ordered, first)
+	for _, addr := range addrs {
+		if addr != first {
+			ordered = append(ordered, addr)
+		}
+	}
+	return ordered
+}
@@ -0,0 +1,23 @@
+package rpc
+
+import "hash/fnv"
+
+// WorkerForRange deterministically maps a [start, end) term range to one
+// of addrs, so the same range is always assigned to the same worker when
+// checkpointing is enabled, without the coordinator needing to keep its
+// own assignment table.
+func WorkerForRange(addrs []string, start, end int64) string {
+	if len(addrs) == 0 {
+		return ""
+	}
+
+	h := fnv.New64a()
+	var buf [16]byte
+	for i := 0; i < 8; i++ {
+		buf[i] = byte(start >> (56 - 8*i))
+		buf[8+i] = byte(end >> (56 - 8*i))
+	}
+	_, _ = h.Write(buf[:])
+
+	return addrs[h.Sum64()%uint64(len(addrs))]
+}
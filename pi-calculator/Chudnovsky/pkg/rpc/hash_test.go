@@ -0,0 +1,46 @@
+package rpc
+
+import "testing"
+
+func TestWorkerForRange_Deterministic(t *testing.T) {
+	addrs := []string{"worker-a:9000", "worker-b:9000", "worker-c:9000"}
+
+	first := WorkerForRange(addrs, 100, 200)
+	second := WorkerForRange(addrs, 100, 200)
+	if first != second {
+		t.Errorf("Expected the same range to always map to the same worker, got %q and %q", first, second)
+	}
+}
+
+func TestWorkerForRange_UsesFullAddrSet(t *testing.T) {
+	addrs := []string{"worker-a:9000", "worker-b:9000", "worker-c:9000"}
+
+	seen := make(map[string]bool)
+	for start := int64(0); start < 200; start++ {
+		seen[WorkerForRange(addrs, start, start+1)] = true
+	}
+
+	if len(seen) != len(addrs) {
+		t.Errorf("Expected ranges to spread across all %d workers, only saw %d", len(addrs), len(seen))
+	}
+}
+
+func TestWorkerForRange_EmptyAddrs(t *testing.T) {
+	if got := WorkerForRange(nil, 0, 10); got != "" {
+		t.Errorf("Expected empty string for no addresses, got %q", got)
+	}
+}
+
+func TestWorkerForRange_DistinctRangesCanDiffer(t *testing.T) {
+	addrs := []string{"worker-a:9000", "worker-b:9000"}
+
+	a := WorkerForRange(addrs, 0, 10)
+	b := WorkerForRange(addrs, 10, 20)
+	// Not a hard guarantee for every pair of ranges, but with only two
+	// addresses and these particular bounds the hash should land on
+	// different workers; this is a smoke check that start/end both feed
+	// the hash rather than one being ignored.
+	if a == b {
+		t.Skip("both ranges happened to hash to the same worker; not a failure, just uninformative")
+	}
+}
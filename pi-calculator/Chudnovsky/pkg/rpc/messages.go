@@ -0,0 +1,65 @@
+// Package rpc lets a Chudnovsky coordinator dispatch PQT term ranges to
+// worker processes on other machines, as a drop-in calculator.PoolInterface
+// alongside the in-process internal/workerpool.Pool.
+//
+// Deviation from the original ask: this is mutual-TLS net/rpc, not gRPC.
+// chudnovsky.proto is still the source-of-truth schema and the intended
+// future wire contract, but this build environment has no protoc/grpc
+// toolchain to generate real stubs from it, so coordinator.go/worker.go
+// hand-implement the same request/response shapes over net/rpc instead.
+// Revisit once protoc/grpc-go codegen is available; swapping the transport
+// then should only touch coordinator.go/worker.go, not callers of this
+// package or chudnovsky.proto itself.
+package rpc
+
+import "math/big"
+
+// BigInt is the sign+magnitude wire representation of a math/big.Int,
+// matching chudnovsky.proto's BigInt message.
+type BigInt struct {
+	Negative  bool
+	Magnitude []byte
+}
+
+// EncodeBigInt converts v into its wire representation.
+func EncodeBigInt(v *big.Int) BigInt {
+	return BigInt{Negative: v.Sign() < 0, Magnitude: v.Bytes()}
+}
+
+// Decode converts b back into a *big.Int.
+func (b BigInt) Decode() *big.Int {
+	v := new(big.Int).SetBytes(b.Magnitude)
+	if b.Negative {
+		v.Neg(v)
+	}
+	return v
+}
+
+// ComputeRangeArgs is the request for WorkerService.ComputeRange: compute
+// the Chudnovsky PQT contribution of the term range [Start, End).
+type ComputeRangeArgs struct {
+	Start, End int64
+}
+
+// ComputeRangeReply carries the P, Q, T result of a completed range.
+type ComputeRangeReply struct {
+	P, Q, T BigInt
+}
+
+// ProgressArgs requests a worker's current cumulative progress count.
+type ProgressArgs struct{}
+
+// ProgressReply reports a worker's cumulative term-progress count (the last
+// TermCompleted.Index its calculator.FuncReporter saw), cumulative rather
+// than a delta so a missed poll doesn't lose progress.
+type ProgressReply struct {
+	Current int64
+}
+
+// HealthArgs requests a liveness check.
+type HealthArgs struct{}
+
+// HealthReply reports whether a worker is alive and accepting work.
+type HealthReply struct {
+	Alive bool
+}
@@ -0,0 +1,440 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"net/rpc"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/calculator"
+)
+
+// testCA is a self-signed certificate authority generated fresh per test
+// run, used to issue both a worker's server certificate and a
+// coordinator's client certificate so mutual TLS can be exercised without
+// depending on certificate files on disk.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newCA() (*testCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate CA key: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("create CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}, nil
+}
+
+// issueCert mints a leaf certificate for localhost, signed by ca, valid for
+// the given extended key usages (server or client auth).
+func (ca *testCA) issueCert(cn string, extKeyUsage []x509.ExtKeyUsage) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("generate leaf key: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  extKeyUsage,
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("create leaf certificate: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// clientConfig builds a client-side tls.Config presenting cn's certificate
+// and trusting ca, matching coordinatorTLSConfig in cmd/chudnovsky/main.go.
+func (ca *testCA) clientConfig(cn string) (*tls.Config, error) {
+	cert, err := ca.issueCert(cn, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      ca.pool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// serverConfig builds a server-side tls.Config requiring and verifying a
+// client certificate against ca, matching workerTLSConfig in
+// cmd/chudnovskyworker/main.go.
+func (ca *testCA) serverConfig() (*tls.Config, error) {
+	cert, err := ca.issueCert("worker", []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    ca.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
+
+// newTestCA and clientTLSConfig are thin testing.T-aware wrappers around
+// newCA/clientConfig for use from inside a test body.
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	ca, err := newCA()
+	if err != nil {
+		t.Fatalf("newCA: %v", err)
+	}
+	return ca
+}
+
+func (ca *testCA) clientTLSConfig(t *testing.T, cn string) *tls.Config {
+	t.Helper()
+	cfg, err := ca.clientConfig(cn)
+	if err != nil {
+		t.Fatalf("clientConfig: %v", err)
+	}
+	return cfg
+}
+
+func (ca *testCA) serverTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+	cfg, err := ca.serverConfig()
+	if err != nil {
+		t.Fatalf("serverConfig: %v", err)
+	}
+	return cfg
+}
+
+// net/rpc registers services against a single process-wide DefaultServer,
+// so ListenAndServe (which always calls the package-level rpc.Register)
+// can only succeed once per test binary. TestMain starts one real
+// WorkerService behind ListenAndServe before any test runs and tears it
+// down after the whole package finishes, so it's shared by every test that
+// just needs a single working worker to dial. Tests that need several
+// distinct worker endpoints at once (for retry/eviction) instead use
+// startFakeWorker, which registers against its own private *rpc.Server and
+// so doesn't collide with the DefaultServer or with itself.
+var (
+	realWorkerAddr string
+	realWorkerCA   *testCA
+)
+
+func TestMain(m *testing.M) {
+	ca, err := newCA()
+	if err != nil {
+		panic(err)
+	}
+	serverCfg, err := ca.serverConfig()
+	if err != nil {
+		panic(err)
+	}
+	realWorkerCA = ca
+
+	ctx, cancel := context.WithCancel(context.Background())
+	server, err := ListenAndServe(ctx, "127.0.0.1:0", serverCfg, NewWorkerService())
+	if err != nil {
+		panic(err)
+	}
+	realWorkerAddr = server.listener.Addr().String()
+
+	code := m.Run()
+
+	cancel()
+	_ = server.Close()
+	os.Exit(code)
+}
+
+func startRealWorker(t *testing.T) (addr string, ca *testCA) {
+	t.Helper()
+	return realWorkerAddr, realWorkerCA
+}
+
+// startFakeWorker serves service (any type exposing WorkerService's method
+// set, including *WorkerService itself) over mutually-authenticated TLS
+// using a private *rpc.Server, so multiple distinct worker endpoints can
+// coexist in one test process without colliding on net/rpc's DefaultServer
+// registry the way a second ListenAndServe call would.
+func startFakeWorker(t *testing.T, ca *testCA, service any) (addr string) {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("WorkerService", service); err != nil {
+		t.Fatalf("register fake worker: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", ca.serverTLSConfig(t))
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.ServeConn(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// deadAddr returns an address nothing is listening on, by binding and
+// immediately releasing it, so dialing it reliably fails with connection
+// refused rather than risking a collision with an address already in use.
+func deadAddr(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("reserve a port: %v", err)
+	}
+	addr := l.Addr().String()
+	if err := l.Close(); err != nil {
+		t.Fatalf("release reserved port: %v", err)
+	}
+	return addr
+}
+
+// firstChoiceRange searches for a [start,start+1) range whose
+// WorkerForRange first pick among addrs is want, so a retry/eviction test
+// can force dispatch to try want first instead of depending on which
+// address an arbitrary range happens to hash to.
+func firstChoiceRange(t *testing.T, addrs []string, want string) int64 {
+	t.Helper()
+	for start := int64(0); start < 10000; start++ {
+		if WorkerForRange(addrs, start, start+1) == want {
+			return start
+		}
+	}
+	t.Fatalf("could not find a range whose first-choice worker is %s", want)
+	return 0
+}
+
+func TestWorkerService_ComputeRangeMatchesSequential(t *testing.T) {
+	addr, ca := startRealWorker(t)
+
+	conn, err := tls.Dial("tcp", addr, ca.clientTLSConfig(t, "coordinator"))
+	if err != nil {
+		t.Fatalf("dial worker: %v", err)
+	}
+	defer conn.Close()
+	rpcClient := rpc.NewClient(conn)
+	defer rpcClient.Close()
+
+	var reply ComputeRangeReply
+	if err := rpcClient.Call("WorkerService.ComputeRange", ComputeRangeArgs{Start: 0, End: 20}, &reply); err != nil {
+		t.Fatalf("ComputeRange: %v", err)
+	}
+
+	wantP, wantQ, wantT := calculator.ComputePQTSequential(0, 20)
+	if reply.P.Decode().Cmp(wantP) != 0 || reply.Q.Decode().Cmp(wantQ) != 0 || reply.T.Decode().Cmp(wantT) != 0 {
+		t.Error("Expected the worker's P/Q/T to match a local sequential computation")
+	}
+}
+
+func TestWorkerService_HealthCheck(t *testing.T) {
+	addr, ca := startRealWorker(t)
+
+	conn, err := tls.Dial("tcp", addr, ca.clientTLSConfig(t, "coordinator"))
+	if err != nil {
+		t.Fatalf("dial worker: %v", err)
+	}
+	defer conn.Close()
+	rpcClient := rpc.NewClient(conn)
+	defer rpcClient.Close()
+
+	var reply HealthReply
+	if err := rpcClient.Call("WorkerService.HealthCheck", HealthArgs{}, &reply); err != nil {
+		t.Fatalf("HealthCheck: %v", err)
+	}
+	if !reply.Alive {
+		t.Error("Expected a running worker to report itself alive")
+	}
+}
+
+func TestDial_RejectsUntrustedClientCertificate(t *testing.T) {
+	addr, ca := startRealWorker(t)
+
+	otherCA := newTestCA(t)
+	untrusted := otherCA.clientTLSConfig(t, "coordinator")
+	// Trust the real worker's CA for server verification, but present a
+	// client certificate signed by a different CA the worker doesn't trust.
+	untrusted.RootCAs = ca.pool
+
+	// TLS 1.3 completes the client's side of the handshake before the
+	// server finishes verifying the client certificate, so a bad cert
+	// does not necessarily surface as a Dial error; the worker instead
+	// closes the connection once it rejects the certificate, which only
+	// becomes visible on the first subsequent read or write.
+	conn, err := tls.Dial("tcp", addr, untrusted)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	client := rpc.NewClient(conn)
+	defer client.Close()
+	var reply HealthReply
+	if err := client.Call("WorkerService.HealthCheck", HealthArgs{}, &reply); err == nil {
+		t.Fatal("Expected the worker to reject a client certificate signed by an untrusted CA")
+	}
+}
+
+func TestCoordinator_DispatchSucceeds(t *testing.T) {
+	addr, ca := startRealWorker(t)
+
+	coord := NewCoordinator([]string{addr}, ca.clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	result := <-coord.Submit(0, 10, nil)
+	if result.Err != nil {
+		t.Fatalf("Unexpected error: %v", result.Err)
+	}
+
+	wantP, wantQ, wantT := calculator.ComputePQTSequential(0, 10)
+	if result.P.Cmp(wantP) != 0 || result.Q.Cmp(wantQ) != 0 || result.T.Cmp(wantT) != 0 {
+		t.Error("Expected the coordinator's result to match a local sequential computation")
+	}
+}
+
+func TestCoordinator_FallsBackAfterUnreachableWorker(t *testing.T) {
+	ca := newTestCA(t)
+	dead := deadAddr(t)
+	live := startFakeWorker(t, ca, NewWorkerService())
+	addrs := []string{dead, live}
+
+	start := firstChoiceRange(t, addrs, dead)
+
+	coord := NewCoordinator(addrs, ca.clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	result := <-coord.Submit(start, start+1, nil)
+	if result.Err != nil {
+		t.Fatalf("Expected dispatch to fall back to the reachable worker, got error: %v", result.Err)
+	}
+}
+
+func TestCoordinator_DispatchFailsWhenAllWorkersUnreachable(t *testing.T) {
+	coord := NewCoordinator([]string{deadAddr(t), deadAddr(t)}, newTestCA(t).clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	result := <-coord.Submit(0, 1, nil)
+	if result.Err == nil {
+		t.Error("Expected an error when every worker is unreachable")
+	}
+}
+
+func TestCoordinator_EvictsUnreachableClientAfterFailure(t *testing.T) {
+	dead := deadAddr(t)
+	coord := NewCoordinator([]string{dead}, newTestCA(t).clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	if _, err := coord.clientFor(dead); err == nil {
+		t.Fatal("Expected dialing a dead address to fail")
+	}
+
+	result := <-coord.Submit(0, 1, nil)
+	if result.Err == nil {
+		t.Fatal("Expected an error dispatching to an unreachable worker")
+	}
+
+	coord.mu.Lock()
+	_, cached := coord.clients[dead]
+	coord.mu.Unlock()
+	if cached {
+		t.Error("Expected a failed client not to be cached for reuse")
+	}
+}
+
+func TestCoordinator_Alive(t *testing.T) {
+	ca := newTestCA(t)
+	live := startFakeWorker(t, ca, NewWorkerService())
+	dead := deadAddr(t)
+
+	coord := NewCoordinator([]string{dead, live}, ca.clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	if !coord.Alive() {
+		t.Error("Expected Alive to report true when at least one worker answers a health check")
+	}
+}
+
+func TestCoordinator_NotAliveWhenAllWorkersUnreachable(t *testing.T) {
+	coord := NewCoordinator([]string{deadAddr(t), deadAddr(t)}, newTestCA(t).clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	if coord.Alive() {
+		t.Error("Expected Alive to report false when no worker answers a health check")
+	}
+}
+
+// failingWorker implements WorkerService's method set but always fails
+// ComputeRange, so TestCoordinator_FallsBackAfterWorkerErrorReply can
+// simulate a worker that's reachable but broken, as opposed to deadAddr's
+// unreachable case.
+type failingWorker struct{}
+
+func (failingWorker) ComputeRange(_ ComputeRangeArgs, _ *ComputeRangeReply) error {
+	return context.DeadlineExceeded
+}
+
+func (failingWorker) Progress(_ ProgressArgs, reply *ProgressReply) error {
+	reply.Current = 0
+	return nil
+}
+
+func (failingWorker) HealthCheck(_ HealthArgs, reply *HealthReply) error {
+	reply.Alive = true
+	return nil
+}
+
+func TestCoordinator_FallsBackAfterWorkerErrorReply(t *testing.T) {
+	ca := newTestCA(t)
+	failingAddr := startFakeWorker(t, ca, failingWorker{})
+	liveAddr := startFakeWorker(t, ca, NewWorkerService())
+	addrs := []string{failingAddr, liveAddr}
+
+	start := firstChoiceRange(t, addrs, failingAddr)
+
+	coord := NewCoordinator(addrs, ca.clientTLSConfig(t, "coordinator"), nil)
+	defer coord.Close()
+
+	result := <-coord.Submit(start, start+1, nil)
+	if result.Err != nil {
+		t.Fatalf("Expected dispatch to fall back past a worker that errors, got: %v", result.Err)
+	}
+}
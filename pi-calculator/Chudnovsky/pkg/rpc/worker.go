@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/rpc"
+	"sync/atomic"
+
+	"github.com/vuhung16au/math-olympiad-ml/pi-calculator/Chudnovsky/internal/calculator"
+)
+
+// WorkerService is the net/rpc service a worker process registers and
+// serves over TLS. Its method set (ComputeRange, Progress, HealthCheck)
+// mirrors chudnovsky.proto's ChudnovskyWorker service.
+//
+// net/rpc runs each incoming call on its own goroutine, so several
+// ComputeRange calls can run at once; progress is tracked in a single
+// atomic counter shared by all of them rather than per-range, fed by a
+// calculator.FuncReporter attached to each call's context rather than a
+// package-level callback.
+type WorkerService struct {
+	progress int64
+}
+
+// NewWorkerService creates a WorkerService.
+func NewWorkerService() *WorkerService {
+	return &WorkerService{}
+}
+
+// ComputeRange computes the PQT contribution of [args.Start, args.End)
+// using calculator.ComputePQTSequentialCtx with a progress reporter
+// attached, then encodes the result for the wire.
+func (w *WorkerService) ComputeRange(args ComputeRangeArgs, reply *ComputeRangeReply) error {
+	reporter := calculator.NewFuncReporter(func(e calculator.Event) {
+		if term, ok := e.(calculator.TermCompleted); ok {
+			atomic.StoreInt64(&w.progress, term.Index)
+		}
+	})
+	ctx := calculator.AttachProgressReporter(context.Background(), reporter)
+
+	p, q, t, err := calculator.ComputePQTSequentialCtx(ctx, args.Start, args.End)
+	if err != nil {
+		return err
+	}
+	*reply = ComputeRangeReply{P: EncodeBigInt(p), Q: EncodeBigInt(q), T: EncodeBigInt(t)}
+	return nil
+}
+
+// Progress reports the worker's current cumulative term-progress count.
+func (w *WorkerService) Progress(_ ProgressArgs, reply *ProgressReply) error {
+	reply.Current = atomic.LoadInt64(&w.progress)
+	return nil
+}
+
+// HealthCheck always reports the worker as alive; a worker that's stuck
+// or dead stops answering RPCs entirely, which the coordinator's
+// per-call error handling already treats as a failed/unreachable worker.
+func (w *WorkerService) HealthCheck(_ HealthArgs, reply *HealthReply) error {
+	reply.Alive = true
+	return nil
+}
+
+// Server listens for coordinator connections and serves a WorkerService
+// over mutually-authenticated TLS.
+type Server struct {
+	listener net.Listener
+}
+
+// ListenAndServe registers service under net/rpc's default server, starts
+// a TLS listener on addr, and serves connections until ctx is done or
+// Close is called. tlsConfig should require and verify the coordinator's
+// client certificate (mutual auth).
+func ListenAndServe(ctx context.Context, addr string, tlsConfig *tls.Config, service *WorkerService) (*Server, error) {
+	if err := rpc.Register(service); err != nil {
+		return nil, err
+	}
+
+	listener, err := tls.Listen("tcp", addr, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{listener: listener}
+
+	go func() {
+		<-ctx.Done()
+		_ = s.listener.Close()
+	}()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go rpc.ServeConn(conn)
+		}
+	}()
+
+	return s, nil
+}
+
+// Close stops accepting new connections.
+func (s *Server) Close() error {
+	return s.listener.Close()
+}